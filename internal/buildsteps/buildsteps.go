@@ -0,0 +1,68 @@
+// Package buildsteps computes the changes turning the build steps of a Build Configuration into the configured ones, so
+// existing steps are updated in place rather than being deleted and created again.
+package buildsteps
+
+// Step identifies a build step
+type Step struct {
+	ID   string
+	Name string
+	Type string
+
+	// Inherited is set for the steps a Build Configuration inherits from its templates
+	Inherited bool
+}
+
+// Match returns, for each step of to, the index of the step of from it updates, or -1 when it's created. A step of to
+// matches the step of from with the same ID and runner type, or else the one with the same name and runner type, so
+// steps are updated when they're renamed or reordered. It also returns the indexes of the steps of from which aren't
+// matched, and so are deleted.
+func Match(from, to []Step) (matches []int, deleted []int) {
+	claimed := make(map[int]bool)
+	matches = make([]int, len(to))
+	for i := range matches {
+		matches[i] = -1
+	}
+
+	claim := func(i int, matching func(o Step) bool) {
+		for j, o := range from {
+			if o.ID != "" && !claimed[j] && matching(o) {
+				matches[i] = j
+				claimed[j] = true
+				return
+			}
+		}
+	}
+	for i, s := range to {
+		if s.ID != "" {
+			claim(i, func(o Step) bool { return o.ID == s.ID && o.Type == s.Type })
+		}
+	}
+	for i, s := range to {
+		if matches[i] == -1 && s.Name != "" {
+			claim(i, func(o Step) bool { return o.Name == s.Name && o.Type == s.Type })
+		}
+	}
+
+	for j, o := range from {
+		if o.ID != "" && !claimed[j] {
+			deleted = append(deleted, j)
+		}
+	}
+	return matches, deleted
+}
+
+// OrderChanged returns whether the steps of current which aren't inherited from templates run in another order than the
+// one of the IDs in order
+func OrderChanged(current []Step, order []string) bool {
+	next := 0
+	for _, s := range current {
+		if s.Inherited {
+			continue
+		}
+		if next >= len(order) || s.ID != order[next] {
+			return true
+		}
+		next++
+	}
+	return next != len(order)
+}
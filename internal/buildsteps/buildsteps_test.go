@@ -0,0 +1,129 @@
+package buildsteps
+
+import (
+	"reflect"
+	"testing"
+)
+
+var (
+	build   = Step{ID: "RUNNER_1", Name: "build", Type: "simpleRunner"}
+	test    = Step{ID: "RUNNER_2", Name: "test", Type: "gradle-runner"}
+	publish = Step{ID: "RUNNER_3", Name: "publish", Type: "simpleRunner"}
+)
+
+func TestMatch(t *testing.T) {
+	cases := map[string]struct {
+		from, to []Step
+		matches  []int
+		deleted  []int
+	}{
+		"unchanged": {
+			from:    []Step{build, test},
+			to:      []Step{build, test},
+			matches: []int{0, 1},
+		},
+		"rename": {
+			from:    []Step{build, test},
+			to:      []Step{build, {ID: "RUNNER_2", Name: "unit tests", Type: "gradle-runner"}},
+			matches: []int{0, 1},
+		},
+		"reorder": {
+			from:    []Step{build, test, publish},
+			to:      []Step{test, build, publish},
+			matches: []int{1, 0, 2},
+		},
+		"reorder by name": {
+			// the IDs of the steps in the state follow their positions rather than their names
+			from:    []Step{build, test},
+			to:      []Step{{ID: "RUNNER_1", Name: "test", Type: "gradle-runner"}, {ID: "RUNNER_2", Name: "build", Type: "simpleRunner"}},
+			matches: []int{1, 0},
+		},
+		"insert": {
+			from:    []Step{build, publish},
+			to:      []Step{build, {Name: "test", Type: "gradle-runner"}, publish},
+			matches: []int{0, -1, 1},
+		},
+		"delete": {
+			from:    []Step{build, test, publish},
+			to:      []Step{build, publish},
+			matches: []int{0, 2},
+			deleted: []int{1},
+		},
+		"replace runner": {
+			from:    []Step{build},
+			to:      []Step{{ID: "RUNNER_1", Name: "compile", Type: "Maven2"}},
+			matches: []int{-1},
+			deleted: []int{0},
+		},
+		"replace runner keeping the name": {
+			from:    []Step{build},
+			to:      []Step{{ID: "RUNNER_1", Name: "build", Type: "Maven2"}},
+			matches: []int{-1},
+			deleted: []int{0},
+		},
+		"new steps": {
+			from:    []Step{{Name: "build", Type: "simpleRunner"}},
+			to:      []Step{{Name: "build", Type: "simpleRunner"}},
+			matches: []int{-1},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			matches, deleted := Match(c.from, c.to)
+			if !reflect.DeepEqual(matches, c.matches) {
+				t.Errorf("expected matches %v but got %v", c.matches, matches)
+			}
+			if !reflect.DeepEqual(deleted, c.deleted) {
+				t.Errorf("expected deleted %v but got %v", c.deleted, deleted)
+			}
+		})
+	}
+}
+
+func TestOrderChanged(t *testing.T) {
+	inherited := Step{ID: "RUNNER_9", Name: "checkout", Type: "simpleRunner", Inherited: true}
+
+	cases := map[string]struct {
+		current []Step
+		order   []string
+		changed bool
+	}{
+		"same order": {
+			current: []Step{build, test},
+			order:   []string{"RUNNER_1", "RUNNER_2"},
+		},
+		"reordered": {
+			current: []Step{build, test},
+			order:   []string{"RUNNER_2", "RUNNER_1"},
+			changed: true,
+		},
+		"inherited steps are ignored": {
+			current: []Step{inherited, build, test},
+			order:   []string{"RUNNER_1", "RUNNER_2"},
+		},
+		"inserted": {
+			current: []Step{build, publish, test},
+			order:   []string{"RUNNER_1", "RUNNER_2", "RUNNER_3"},
+			changed: true,
+		},
+		"missing step": {
+			current: []Step{build},
+			order:   []string{"RUNNER_1", "RUNNER_2"},
+			changed: true,
+		},
+		"extra step": {
+			current: []Step{build, test},
+			order:   []string{"RUNNER_1"},
+			changed: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if actual := OrderChanged(c.current, c.order); actual != c.changed {
+				t.Errorf("expected %t but got %t", c.changed, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Package cache provides a concurrency-safe cache which coalesces concurrent loads of the same key.
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Cache stores the result of loading a key until it's invalidated. Concurrent calls to Get for a key which is being
+// loaded wait for, and share, the result of the load in progress rather than loading it again.
+type Cache struct {
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+type entry struct {
+	// done is closed once value and err are set
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// New returns an empty Cache
+func New() *Cache {
+	return &Cache{
+		entries: make(map[string]*entry),
+	}
+}
+
+// Get returns the value cached for key, calling load to retrieve it when it isn't cached nor being loaded.
+// Errors returned by load aren't cached, so the next call to Get for key loads it again.
+//
+// As its result is shared, load isn't cancelled with ctx: it's called with a context carrying the values of ctx, but
+// neither its deadline nor its cancellation. Get returns as soon as ctx is done, without waiting for load to finish.
+func (c *Cache) Get(ctx context.Context, key string, load func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if !ok {
+		e = &entry{done: make(chan struct{})}
+		c.entries[key] = e
+		go c.load(detachedContext{ctx}, key, e, load)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-e.done:
+		return e.value, e.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *Cache) load(ctx context.Context, key string, e *entry, load func(ctx context.Context) (interface{}, error)) {
+	value, err := load(ctx)
+	if err != nil {
+		c.mu.Lock()
+		// the entry may have been invalidated, and replaced by another load, in the meantime
+		if c.entries[key] == e {
+			delete(c.entries, key)
+		}
+		c.mu.Unlock()
+	}
+
+	e.value, e.err = value, err
+	close(e.done)
+}
+
+// Invalidate removes the value cached for key, so the next call to Get loads it again. Callers already waiting on
+// a load in progress still receive its result.
+func (c *Cache) Invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// InvalidateAll removes every cached value
+func (c *Cache) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*entry)
+}
+
+// detachedContext keeps the values of a context, such as the logger of the request, without its deadline and
+// cancellation
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+
+func (detachedContext) Done() <-chan struct{} { return nil }
+
+func (detachedContext) Err() error { return nil }
@@ -0,0 +1,155 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCache_CoalescesConcurrentLoads(t *testing.T) {
+	c := New()
+	var loads int32
+	release := make(chan struct{})
+
+	load := func(context.Context) (interface{}, error) {
+		atomic.AddInt32(&loads, 1)
+		<-release
+		return "value", nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]interface{}, 10)
+	started := make(chan struct{}, len(results))
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			started <- struct{}{}
+			results[i], _ = c.Get(context.Background(), "key", load)
+		}(i)
+	}
+	for range results {
+		<-started
+	}
+	close(release)
+	wg.Wait()
+
+	if loads != 1 {
+		t.Fatalf("expected a single load but got %d", loads)
+	}
+	for i, r := range results {
+		if r != "value" {
+			t.Errorf("result %d: expected %q but got %v", i, "value", r)
+		}
+	}
+}
+
+func TestCache_DoesNotCacheErrors(t *testing.T) {
+	c := New()
+	loads := 0
+
+	load := func(context.Context) (interface{}, error) {
+		loads++
+		if loads == 1 {
+			return nil, errors.New("boom")
+		}
+		return "value", nil
+	}
+
+	if _, err := c.Get(context.Background(), "key", load); err == nil {
+		t.Fatalf("expected the first load to fail")
+	}
+	v, err := c.Get(context.Background(), "key", load)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if v != "value" || loads != 2 {
+		t.Fatalf("expected the value to be loaded again but got %v after %d loads", v, loads)
+	}
+}
+
+func TestCache_Invalidate(t *testing.T) {
+	c := New()
+	loads := 0
+
+	load := func(context.Context) (interface{}, error) {
+		loads++
+		return loads, nil
+	}
+
+	c.Get(context.Background(), "key", load)
+	c.Get(context.Background(), "other", load)
+	if v, _ := c.Get(context.Background(), "key", load); v != 1 {
+		t.Fatalf("expected the cached value but got %v", v)
+	}
+
+	c.Invalidate("key")
+	if v, _ := c.Get(context.Background(), "key", load); v != 3 {
+		t.Fatalf("expected the value to be loaded again but got %v", v)
+	}
+	if v, _ := c.Get(context.Background(), "other", load); v != 2 {
+		t.Fatalf("expected other keys to remain cached but got %v", v)
+	}
+
+	c.InvalidateAll()
+	if v, _ := c.Get(context.Background(), "other", load); v != 4 {
+		t.Fatalf("expected the value to be loaded again but got %v", v)
+	}
+}
+
+func TestCache_WaitHonorsContext(t *testing.T) {
+	c := New()
+	release := make(chan struct{})
+	defer close(release)
+
+	loading := make(chan struct{})
+	go c.Get(context.Background(), "key", func(context.Context) (interface{}, error) {
+		close(loading)
+		<-release
+		return "value", nil
+	})
+	<-loading
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.Get(ctx, "key", nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the wait to be cancelled but got %v", err)
+	}
+}
+
+func TestCache_LoadOutlivesCancelledCaller(t *testing.T) {
+	c := New()
+	release := make(chan struct{})
+	loading := make(chan struct{})
+	load := func(ctx context.Context) (interface{}, error) {
+		close(loading)
+		<-release
+		return "value", ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		_, err := c.Get(ctx, "key", load)
+		first <- err
+	}()
+	<-loading
+
+	second := make(chan interface{})
+	go func() {
+		v, _ := c.Get(context.Background(), "key", nil)
+		second <- v
+	}()
+
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the first caller to be cancelled but got %v", err)
+	}
+
+	close(release)
+	if v := <-second; v != "value" {
+		t.Fatalf("expected the other caller to receive the value but got %v", v)
+	}
+}
@@ -0,0 +1,66 @@
+// Package mutexkv provides mutexes identified by a key, so operations on the same remote object can be serialized.
+package mutexkv
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// MutexKV is a collection of mutexes identified by key. Mutexes are created on first use.
+type MutexKV struct {
+	mu    sync.Mutex
+	store map[string]chan struct{}
+}
+
+// New returns an empty MutexKV
+func New() *MutexKV {
+	return &MutexKV{
+		store: make(map[string]chan struct{}),
+	}
+}
+
+// Lock locks the mutex for key, blocking until it's available
+func (m *MutexKV) Lock(key string) {
+	log.Printf("[DEBUG] Locking %q", key)
+	m.get(key) <- struct{}{}
+	log.Printf("[DEBUG] Locked %q", key)
+}
+
+// LockContext locks the mutex for key, blocking until it's available or ctx is done. It returns ctx.Err() when the
+// mutex couldn't be locked before ctx was done.
+func (m *MutexKV) LockContext(ctx context.Context, key string) error {
+	log.Printf("[DEBUG] Locking %q", key)
+	select {
+	case m.get(key) <- struct{}{}:
+		log.Printf("[DEBUG] Locked %q", key)
+		return nil
+	case <-ctx.Done():
+		log.Printf("[DEBUG] Gave up locking %q: %s", key, ctx.Err())
+		return ctx.Err()
+	}
+}
+
+// Unlock unlocks the mutex for key
+func (m *MutexKV) Unlock(key string) {
+	log.Printf("[DEBUG] Unlocking %q", key)
+	select {
+	case <-m.get(key):
+	default:
+		panic("mutexkv: unlock of unlocked mutex " + key)
+	}
+	log.Printf("[DEBUG] Unlocked %q", key)
+}
+
+// get returns the mutex for key, which is locked by sending to it
+func (m *MutexKV) get(key string) chan struct{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mutex, ok := m.store[key]
+	if !ok {
+		mutex = make(chan struct{}, 1)
+		m.store[key] = mutex
+	}
+	return mutex
+}
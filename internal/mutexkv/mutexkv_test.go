@@ -0,0 +1,68 @@
+package mutexkv
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMutexKV_SerializesSameKey(t *testing.T) {
+	m := New()
+	m.Lock("buildType/Project_Build")
+
+	locked := make(chan struct{})
+	go func() {
+		m.Lock("buildType/Project_Build")
+		close(locked)
+	}()
+
+	select {
+	case <-locked:
+		t.Fatalf("expected the second lock to block until the first is released")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	m.Unlock("buildType/Project_Build")
+
+	select {
+	case <-locked:
+	case <-time.After(time.Second):
+		t.Fatalf("expected the second lock to be acquired once the first was released")
+	}
+}
+
+func TestMutexKV_DifferentKeysDontBlock(t *testing.T) {
+	m := New()
+	m.Lock("buildType/Project_Build")
+	defer m.Unlock("buildType/Project_Build")
+
+	locked := make(chan struct{})
+	go func() {
+		m.Lock("buildType/Project_Deploy")
+		close(locked)
+	}()
+
+	select {
+	case <-locked:
+	case <-time.After(time.Second):
+		t.Fatalf("expected a lock on a different key not to block")
+	}
+}
+
+func TestMutexKV_LockContextHonorsContext(t *testing.T) {
+	m := New()
+	m.Lock("buildType/Project_Build")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := m.LockContext(ctx, "buildType/Project_Build"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the lock to be given up when the context is done but got %v", err)
+	}
+
+	m.Unlock("buildType/Project_Build")
+	if err := m.LockContext(context.Background(), "buildType/Project_Build"); err != nil {
+		t.Fatalf("expected the lock to be acquired once released but got %v", err)
+	}
+	m.Unlock("buildType/Project_Build")
+}
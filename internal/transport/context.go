@@ -0,0 +1,28 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+)
+
+// ContextTransport is a http.RoundTripper binding every request to a context, for clients building their requests
+// without one. Requests are cancelled along with the context, and honor its deadline.
+type ContextTransport struct {
+	// Next is the underlying transport performing the request
+	Next http.RoundTripper
+
+	ctx context.Context
+}
+
+// NewContextTransport returns a ContextTransport wrapping next, binding requests to ctx
+func NewContextTransport(next http.RoundTripper, ctx context.Context) *ContextTransport {
+	return &ContextTransport{
+		Next: next,
+		ctx:  ctx,
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *ContextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.Next.RoundTrip(req.WithContext(t.ctx))
+}
@@ -0,0 +1,31 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestContextTransport_HonorsDeadline(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	client := &http.Client{Transport: NewContextTransport(http.DefaultTransport, ctx)}
+	_, err := client.Get(server.URL)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the request to be cancelled at the deadline but got %v", err)
+	}
+}
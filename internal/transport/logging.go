@@ -0,0 +1,234 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	redacted = "<redacted>"
+	// maxLoggedBodySize caps the size of a logged body, so downloading a large payload doesn't flood the logs
+	maxLoggedBodySize = 64 * 1024
+)
+
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// TeamCity stores secure values as references to its credentials storage, e.g. "credentialsJSON:0b1b6e7a-..."
+var credentialsJSONTokens = regexp.MustCompile(`credentialsJSON:[\w-]+`)
+
+// LoggingTransport is a http.RoundTripper which logs requests and responses through terraform-plugin-log,
+// so they're emitted according to TF_LOG. Credentials are redacted from logged headers and bodies.
+type LoggingTransport struct {
+	// Next is the underlying transport performing the request
+	Next http.RoundTripper
+
+	// ctx is used to log requests which aren't bound to a context carrying a logger
+	ctx context.Context
+
+	// logBodies is set when debug logs are enabled, as bodies are only read for them
+	logBodies bool
+}
+
+// NewLoggingTransport returns a LoggingTransport wrapping next, logging requests created without a context with ctx
+func NewLoggingTransport(next http.RoundTripper, ctx context.Context) *LoggingTransport {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &LoggingTransport{
+		Next:      next,
+		ctx:       ctx,
+		logBodies: debugLogsEnabled(),
+	}
+}
+
+// debugLogsEnabled returns whether the provider's logs are emitted at the DEBUG level or above, following
+// TF_LOG_PROVIDER or, when it isn't set, TF_LOG. As Terraform does, unknown levels are considered as TRACE.
+func debugLogsEnabled() bool {
+	level := os.Getenv("TF_LOG_PROVIDER")
+	if level == "" {
+		level = os.Getenv("TF_LOG")
+	}
+	switch strings.ToUpper(level) {
+	case "", "OFF", "ERROR", "WARN", "INFO":
+		return false
+	}
+	return true
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if ctx == context.Background() {
+		ctx = t.ctx
+	}
+
+	fields := map[string]interface{}{
+		"tf_http_req_method":  req.Method,
+		"tf_http_req_uri":     req.URL.Redacted(),
+		"tf_http_req_headers": redactHeaders(req.Header),
+	}
+	if t.logBodies {
+		if body, ok := requestBody(req); ok {
+			fields["tf_http_req_body"] = redactBody(body)
+		}
+	}
+	tflog.Debug(ctx, "Sending HTTP request", fields)
+
+	start := time.Now()
+	resp, err := t.Next.RoundTrip(req)
+	duration := time.Since(start)
+
+	if err != nil {
+		tflog.Debug(ctx, "HTTP request failed", map[string]interface{}{
+			"tf_http_req_method":  req.Method,
+			"tf_http_req_uri":     req.URL.Redacted(),
+			"tf_http_duration_ms": duration.Milliseconds(),
+			"error":               err.Error(),
+		})
+		return resp, err
+	}
+
+	fields = map[string]interface{}{
+		"tf_http_req_method":  req.Method,
+		"tf_http_req_uri":     req.URL.Redacted(),
+		"tf_http_res_status":  resp.StatusCode,
+		"tf_http_res_headers": redactHeaders(resp.Header),
+		"tf_http_duration_ms": duration.Milliseconds(),
+	}
+	if t.logBodies {
+		body, err := readResponseBody(resp)
+		if err != nil {
+			return nil, err
+		}
+		if len(body) > 0 {
+			fields["tf_http_res_body"] = redactBody(body)
+		}
+	}
+	tflog.Debug(ctx, "Received HTTP response", fields)
+
+	return resp, nil
+}
+
+// requestBody returns the body of req without consuming it
+func requestBody(req *http.Request) ([]byte, bool) {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody == nil {
+		return nil, false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+	defer body.Close()
+
+	out, err := io.ReadAll(io.LimitReader(body, maxLoggedBodySize))
+	if err != nil {
+		return nil, false
+	}
+	return out, true
+}
+
+// readResponseBody reads up to maxLoggedBodySize bytes of the body of resp, which is replaced so the caller still
+// consumes the bytes read followed by the rest of the body
+func readResponseBody(resp *http.Response) ([]byte, error) {
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return nil, nil
+	}
+	original := resp.Body
+	body, err := io.ReadAll(io.LimitReader(original, maxLoggedBodySize))
+	if err != nil {
+		original.Close()
+		return nil, err
+	}
+	resp.Body = &prefixedBody{Reader: io.MultiReader(bytes.NewReader(body), original), Closer: original}
+	return body, nil
+}
+
+// prefixedBody is a response body whose beginning has already been read from the underlying body
+type prefixedBody struct {
+	io.Reader
+	io.Closer
+}
+
+func redactHeaders(headers http.Header) map[string]string {
+	out := make(map[string]string, len(headers))
+	for k, v := range headers {
+		out[k] = strings.Join(v, ", ")
+	}
+	for _, h := range sensitiveHeaders {
+		if _, ok := out[h]; ok {
+			out[h] = redacted
+		}
+	}
+	return out
+}
+
+// redactBody removes credentials from a request or response body. JSON bodies are redacted structurally, both for
+// plain fields (e.g. "password": "...") and for TeamCity properties (e.g. {"name": "secure:password", "value": "..."}).
+func redactBody(body []byte) string {
+	var parsed interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	// keeps numbers as they are, rather than re-formatting them as floats
+	decoder.UseNumber()
+	if err := decoder.Decode(&parsed); err == nil {
+		var out bytes.Buffer
+		encoder := json.NewEncoder(&out)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(redactJSON(parsed)); err == nil {
+			body = bytes.TrimSpace(out.Bytes())
+		}
+	}
+	return credentialsJSONTokens.ReplaceAllString(string(body), "credentialsJSON:"+redacted)
+}
+
+func redactJSON(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		if name, ok := value["name"].(string); (ok && isSensitiveName(name)) || isPasswordParameter(value) {
+			if _, ok := value["value"]; ok {
+				value["value"] = redacted
+			}
+		}
+		for k, item := range value {
+			if isSensitiveName(k) {
+				value[k] = redacted
+				continue
+			}
+			value[k] = redactJSON(item)
+		}
+		return value
+	case []interface{}:
+		for i, item := range value {
+			value[i] = redactJSON(item)
+		}
+		return value
+	}
+	return v
+}
+
+// isPasswordParameter reports whether v is a parameter whose specification makes it a password, whatever its name
+func isPasswordParameter(v map[string]interface{}) bool {
+	spec, ok := v["type"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	rawValue, ok := spec["rawValue"].(string)
+	return ok && strings.HasPrefix(rawValue, "password")
+}
+
+func isSensitiveName(name string) bool {
+	name = strings.ToLower(name)
+	return strings.HasPrefix(name, "secure:") ||
+		strings.Contains(name, "password") ||
+		strings.Contains(name, "access_token") ||
+		strings.Contains(name, "accesstoken") ||
+		strings.Contains(name, "passphrase")
+}
@@ -0,0 +1,143 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedactBody(t *testing.T) {
+	cases := []struct {
+		body     string
+		expected string
+	}{
+		{
+			body:     `{"name":"Build","id":"Project_Build"}`,
+			expected: `{"id":"Project_Build","name":"Build"}`,
+		},
+		{
+			body:     `{"property":[{"name":"secure:password","value":"hunter2"},{"name":"url","value":"https://example.com"}]}`,
+			expected: `{"property":[{"name":"secure:password","value":"<redacted>"},{"name":"url","value":"https://example.com"}]}`,
+		},
+		{
+			body:     `{"name":"github_access_token","value":"ghp_1234"}`,
+			expected: `{"name":"github_access_token","value":"<redacted>"}`,
+		},
+		{
+			body:     `{"property":[{"name":"env.DEPLOY_TOKEN","value":"hunter2","type":{"rawValue":"password display='hidden'"}},{"name":"env.REGION","value":"eu","type":{"rawValue":"text"}}]}`,
+			expected: `{"property":[{"name":"env.DEPLOY_TOKEN","type":{"rawValue":"password display='hidden'"},"value":"<redacted>"},{"name":"env.REGION","type":{"rawValue":"text"},"value":"eu"}]}`,
+		},
+		{
+			body:     `{"username":"admin","password":"hunter2","count":10000000}`,
+			expected: `{"count":10000000,"password":"<redacted>","username":"admin"}`,
+		},
+		{
+			body:     `{"name":"env.TOKEN","value":"credentialsJSON:0b1b6e7a-4f8e-4c2a-9a42-d2a0c1e5c3f1"}`,
+			expected: `{"name":"env.TOKEN","value":"credentialsJSON:<redacted>"}`,
+		},
+		{
+			body:     `credentialsJSON:0b1b6e7a-4f8e-4c2a-9a42-d2a0c1e5c3f1`,
+			expected: `credentialsJSON:<redacted>`,
+		},
+	}
+
+	for _, c := range cases {
+		if actual := redactBody([]byte(c.body)); actual != c.expected {
+			t.Errorf("redactBody(%s):\nexpected %s\ngot      %s", c.body, c.expected, actual)
+		}
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	headers := http.Header{}
+	headers.Set("Authorization", "Bearer secret")
+	headers.Set("Accept", "application/json")
+
+	actual := redactHeaders(headers)
+	if actual["Authorization"] != redacted {
+		t.Errorf("expected the Authorization header to be redacted but got %q", actual["Authorization"])
+	}
+	if actual["Accept"] != "application/json" {
+		t.Errorf("expected the Accept header to be kept but got %q", actual["Accept"])
+	}
+}
+
+func TestLoggingTransport_PreservesBodies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	t.Setenv("TF_LOG", "DEBUG")
+	client := &http.Client{Transport: NewLoggingTransport(http.DefaultTransport, context.Background())}
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"password":"hunter2"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(body) != `{"password":"hunter2"}` {
+		t.Fatalf("expected the original body to be returned but got %s", body)
+	}
+}
+
+func TestLoggingTransport_PreservesLargeBodies(t *testing.T) {
+	large := strings.Repeat("a", 2*maxLoggedBodySize+1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, large)
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+
+	logged, err := readResponseBody(resp)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(logged) != maxLoggedBodySize {
+		t.Fatalf("expected %d bytes to be logged but got %d", maxLoggedBodySize, len(logged))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(body) != large {
+		t.Fatalf("expected the original body of %d bytes to be returned but got %d bytes", len(large), len(body))
+	}
+}
+
+func TestDebugLogsEnabled(t *testing.T) {
+	cases := []struct {
+		log, logProvider string
+		expected         bool
+	}{
+		{"", "", false},
+		{"INFO", "", false},
+		{"debug", "", true},
+		{"TRACE", "", true},
+		{"JSON", "", true},
+		{"TRACE", "WARN", false},
+		{"", "DEBUG", true},
+	}
+
+	for _, c := range cases {
+		t.Setenv("TF_LOG", c.log)
+		t.Setenv("TF_LOG_PROVIDER", c.logProvider)
+		if actual := debugLogsEnabled(); actual != c.expected {
+			t.Errorf("TF_LOG=%q TF_LOG_PROVIDER=%q: expected %t but got %t", c.log, c.logProvider, c.expected, actual)
+		}
+	}
+}
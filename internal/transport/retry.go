@@ -0,0 +1,188 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"log"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries is the number of times a request is retried when no explicit value is configured
+	DefaultMaxRetries = 4
+	// DefaultMaxRetryWait is the longest time to wait between two attempts when no explicit value is configured
+	DefaultMaxRetryWait = 30 * time.Second
+
+	minRetryWait = 500 * time.Millisecond
+)
+
+// RetryTransport is a http.RoundTripper which retries requests failing with transient errors,
+// waiting between attempts with an exponential backoff and jitter.
+//
+// Only requests which are safe to replay are retried: idempotent requests (GET, HEAD, PUT, DELETE...)
+// are retried on connection errors and on 429/502/503/504 responses, while non-idempotent requests (POST, PATCH)
+// are only retried when the server certainly didn't process them - a failure to connect, or a 429/503 response.
+type RetryTransport struct {
+	// Next is the underlying transport performing each attempt
+	Next http.RoundTripper
+	// MaxRetries is the maximum number of retries after the first attempt
+	MaxRetries int
+	// MaxWait caps the time waited between two attempts, including waits requested through Retry-After
+	MaxWait time.Duration
+}
+
+// NewRetryTransport returns a RetryTransport wrapping next
+func NewRetryTransport(next http.RoundTripper, maxRetries int, maxWait time.Duration) *RetryTransport {
+	return &RetryTransport{
+		Next:       next,
+		MaxRetries: maxRetries,
+		MaxWait:    maxWait,
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		r := req
+		if attempt > 0 {
+			var err error
+			if r, err = rewindBody(req); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := t.Next.RoundTrip(r)
+		if attempt >= t.MaxRetries || !t.shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+		if resp != nil {
+			log.Printf("[DEBUG] %s %s returned %q, retrying in %s (attempt %d of %d)", req.Method, req.URL.Redacted(), resp.Status, wait, attempt+1, t.MaxRetries)
+			drainBody(resp)
+		} else {
+			log.Printf("[DEBUG] %s %s failed: %s, retrying in %s (attempt %d of %d)", req.Method, req.URL.Redacted(), err, wait, attempt+1, t.MaxRetries)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (t *RetryTransport) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// the body can't be replayed
+		return false
+	}
+	if req.Context().Err() != nil {
+		return false
+	}
+
+	idempotent := isIdempotent(req)
+	if err != nil {
+		if isCertificateError(err) {
+			// retrying won't make the server certificate trusted
+			return false
+		}
+		return idempotent || isDialError(err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent
+	}
+	return false
+}
+
+// backoff returns the wait before the next attempt, honouring a Retry-After header if one is present
+func (t *RetryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return t.capWait(wait)
+		}
+	}
+
+	max := t.capWait(time.Duration(float64(minRetryWait) * math.Pow(2, float64(attempt))))
+	// "equal jitter" - spreads retries from concurrent requests so they don't hit a recovering server at once, while
+	// waiting at least half of the backoff
+	return max/2 + time.Duration(rand.Int63n(int64(max/2)+1))
+}
+
+func (t *RetryTransport) capWait(wait time.Duration) time.Duration {
+	if wait < 0 {
+		return 0
+	}
+	if t.MaxWait > 0 && wait > t.MaxWait {
+		return t.MaxWait
+	}
+	return wait
+}
+
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return time.Until(at), true
+	}
+	return 0, false
+}
+
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isDialError reports whether err happened while establishing the connection, meaning nothing was sent to the server
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// rewindBody returns a copy of req with a fresh body, as the body of the previous attempt has been consumed
+func isCertificateError(err error) bool {
+	var verificationErr *tls.CertificateVerificationError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var certErr x509.CertificateInvalidError
+	return errors.As(err, &verificationErr) || errors.As(err, &unknownAuthorityErr) ||
+		errors.As(err, &hostnameErr) || errors.As(err, &certErr)
+}
+
+func rewindBody(req *http.Request) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	if req.GetBody == nil {
+		return r, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	r.Body = body
+	return r, nil
+}
+
+func drainBody(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+}
@@ -0,0 +1,147 @@
+package transport
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport_RetriesTransientFailures(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewRetryTransport(http.DefaultTransport, 4, 10*time.Millisecond)}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200 but got %d", resp.StatusCode)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls but got %d", calls)
+	}
+}
+
+func TestRetryTransport_GivesUpAfterMaxRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewRetryTransport(http.DefaultTransport, 2, 10*time.Millisecond)}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503 but got %d", resp.StatusCode)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls but got %d", calls)
+	}
+}
+
+func TestRetryTransport_DoesNotReplayNonIdempotentRequests(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewRetryTransport(http.DefaultTransport, 4, 10*time.Millisecond)}
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if calls != 1 {
+		t.Fatalf("expected 1 call but got %d", calls)
+	}
+}
+
+func TestRetryTransport_ReplaysBodyWhenServiceUnavailable(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"name":"test"}` {
+			t.Errorf("unexpected body %q", body)
+		}
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewRetryTransport(http.DefaultTransport, 4, 10*time.Millisecond)}
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"name":"test"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200 but got %d", resp.StatusCode)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 calls but got %d", calls)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	cases := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{value: "", ok: false},
+		{value: "5", expected: 5 * time.Second, ok: true},
+		{value: "soon", ok: false},
+	}
+
+	for _, c := range cases {
+		actual, ok := parseRetryAfter(c.value)
+		if ok != c.ok || actual != c.expected {
+			t.Errorf("parseRetryAfter(%q) = (%s, %t), expected (%s, %t)", c.value, actual, ok, c.expected, c.ok)
+		}
+	}
+}
+
+func TestRetryTransport_BackoffBounds(t *testing.T) {
+	transport := NewRetryTransport(http.DefaultTransport, DefaultMaxRetries, 4*time.Second)
+
+	for attempt, max := range []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second} {
+		for i := 0; i < 100; i++ {
+			if wait := transport.backoff(attempt, nil); wait < max/2 || wait > max {
+				t.Fatalf("attempt %d: expected a wait between %s and %s but got %s", attempt, max/2, max, wait)
+			}
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"60"}}}
+	if wait := transport.backoff(0, resp); wait != 4*time.Second {
+		t.Fatalf("expected Retry-After to be capped to 4s but got %s", wait)
+	}
+}
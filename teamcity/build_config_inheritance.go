@@ -0,0 +1,286 @@
+package teamcity
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// inheritedCollections maps the attributes of `disabled_inherited` to the collections of a Build Configuration
+// holding the items which can be inherited from a template
+var inheritedCollections = map[string]string{
+	"steps":              "steps",
+	"triggers":           "triggers",
+	"features":           "features",
+	"agent_requirements": "agent-requirements",
+}
+
+// inheritableItem is an item of a Build Configuration which can be inherited from a template, such as a step or a
+// trigger
+type inheritableItem struct {
+	ID        string `json:"id"`
+	Inherited *bool  `json:"inherited,omitempty"`
+	Disabled  *bool  `json:"disabled,omitempty"`
+}
+
+// inheritableItems is a collection of items, which TeamCity names after the type of the items
+type inheritableItems struct {
+	Steps        []*inheritableItem `json:"step"`
+	Triggers     []*inheritableItem `json:"trigger"`
+	Features     []*inheritableItem `json:"feature"`
+	Requirements []*inheritableItem `json:"agent-requirement"`
+}
+
+func getInheritableItems(r *restClient, buildConfigID string, collection string) (map[string]*inheritableItem, error) {
+	var out inheritableItems
+	if err := r.get(fmt.Sprintf("%s/%s", buildConfigPath(buildConfigID), collection), &out); err != nil {
+		return nil, err
+	}
+	return out.byID(), nil
+}
+
+func (c *inheritableItems) byID() map[string]*inheritableItem {
+	items := make(map[string]*inheritableItem)
+	for _, list := range [][]*inheritableItem{c.Steps, c.Triggers, c.Features, c.Requirements} {
+		for _, i := range list {
+			items[i.ID] = i
+		}
+	}
+	return items
+}
+
+// readInheritableItems reads the items of each of the inheritedCollections from the payload of a Build Configuration,
+// which includes them with their inherited and disabled flags
+func readInheritableItems(payload []byte) (map[string]map[string]*inheritableItem, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]map[string]*inheritableItem, len(inheritedCollections))
+	for _, collection := range inheritedCollections {
+		var items inheritableItems
+		if raw, ok := fields[collection]; ok {
+			if err := json.Unmarshal(raw, &items); err != nil {
+				return nil, err
+			}
+		}
+		out[collection] = items.byID()
+	}
+	return out, nil
+}
+
+func setItemDisabled(r *restClient, buildConfigID string, collection string, itemID string, disabled bool) error {
+	return r.putText(fmt.Sprintf("%s/%s/%s/disabled", buildConfigPath(buildConfigID), collection, itemID), strconv.FormatBool(disabled))
+}
+
+// updateDisabledInherited disables the inherited items added to `disabled_inherited`, and enables the ones removed
+// from it. Only inherited items can be disabled this way, as the Build Configuration manages its own items.
+func updateDisabledInherited(r *restClient, buildConfigID string, o, n []interface{}) error {
+	from, to := expandDisabledInherited(o), expandDisabledInherited(n)
+
+	for attribute, collection := range inheritedCollections {
+		items, err := getInheritableItems(r, buildConfigID, collection)
+		if err != nil {
+			return err
+		}
+
+		for id := range from[attribute] {
+			if to[attribute][id] {
+				continue
+			}
+			// the item is gone when the template it was inherited from has been detached
+			if _, ok := items[id]; !ok {
+				continue
+			}
+			if err := setItemDisabled(r, buildConfigID, collection, id, false); err != nil && !isNotFoundError(err) {
+				return err
+			}
+		}
+		for id := range to[attribute] {
+			item, ok := items[id]
+			if !ok {
+				return fmt.Errorf("`disabled_inherited.%s`: '%s' doesn't exist in Build Configuration '%s'", attribute, id, buildConfigID)
+			}
+			if item.Inherited == nil || !*item.Inherited {
+				return fmt.Errorf("`disabled_inherited.%s`: '%s' isn't inherited from a template by Build Configuration '%s'", attribute, id, buildConfigID)
+			}
+			if item.Disabled != nil && *item.Disabled {
+				continue
+			}
+			if err := setItemDisabled(r, buildConfigID, collection, id, true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func expandDisabledInherited(raw []interface{}) map[string]map[string]bool {
+	out := make(map[string]map[string]bool)
+	for attribute := range inheritedCollections {
+		out[attribute] = make(map[string]bool)
+	}
+	if len(raw) == 0 || raw[0] == nil {
+		return out
+	}
+
+	dt := raw[0].(map[string]interface{})
+	for attribute := range inheritedCollections {
+		if v, ok := dt[attribute].(*schema.Set); ok {
+			for _, id := range v.List() {
+				out[attribute][id.(string)] = true
+			}
+		}
+	}
+	return out
+}
+
+// flattenDisabledInherited returns the inherited items which are disabled in the Build Configuration, given its
+// items by collection
+func flattenDisabledInherited(items map[string]map[string]*inheritableItem) []map[string]interface{} {
+	m := make(map[string]interface{})
+	var count int
+	for attribute, collection := range inheritedCollections {
+		ids := make([]string, 0)
+		for id, i := range items[collection] {
+			if i.Inherited != nil && *i.Inherited && i.Disabled != nil && *i.Disabled {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+		m[attribute] = ids
+		count += len(ids)
+	}
+
+	if count == 0 {
+		return nil
+	}
+	return []map[string]interface{}{m}
+}
+
+func stepParameterPath(buildConfigID string, stepID string, name string) string {
+	return fmt.Sprintf("%s/%s/parameters/%s", buildStepsPath(buildConfigID), stepID, url.PathEscape(name))
+}
+
+// updateInheritedStepParams overrides the properties of the steps inherited from templates set in
+// `inherited_step_params`, and restores the values of the templates for the ones removed from it
+func updateInheritedStepParams(r *restClient, buildConfigID string, templates []string, o, n []interface{}) error {
+	from, to := expandInheritedStepParams(o), expandInheritedStepParams(n)
+
+	steps, err := getBuildSteps(r, buildConfigID)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]*buildStep, len(steps))
+	for _, s := range steps {
+		byID[s.ID] = s
+	}
+
+	for stepID, params := range from {
+		// the step is gone when the template it was inherited from has been detached
+		if _, ok := byID[stepID]; !ok {
+			continue
+		}
+		for name := range params {
+			if _, ok := to[stepID][name]; ok {
+				continue
+			}
+			value, ok, err := templateStepParameter(r, templates, stepID, name)
+			if err != nil {
+				return err
+			}
+			if ok {
+				err = r.putText(stepParameterPath(buildConfigID, stepID, name), value)
+			} else {
+				err = r.delete(stepParameterPath(buildConfigID, stepID, name))
+			}
+			if err != nil && !isNotFoundError(err) {
+				return err
+			}
+		}
+	}
+
+	for stepID, params := range to {
+		step, ok := byID[stepID]
+		if !ok {
+			return fmt.Errorf("`inherited_step_params`: step '%s' doesn't exist in Build Configuration '%s'", stepID, buildConfigID)
+		}
+		if !isInherited(step) {
+			return fmt.Errorf("`inherited_step_params`: step '%s' isn't inherited from a template by Build Configuration '%s'", stepID, buildConfigID)
+		}
+		current := propertiesMap(step.Properties)
+		for name, value := range params {
+			if v, ok := current[name]; ok && v == value {
+				continue
+			}
+			if err := r.putText(stepParameterPath(buildConfigID, stepID, name), value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// templateStepParameter returns the value of a property of a step, as defined by the first of the templates which has
+// the step
+func templateStepParameter(r *restClient, templates []string, stepID string, name string) (string, bool, error) {
+	for _, t := range templates {
+		var step buildStep
+		if err := r.get(fmt.Sprintf("%s/%s", buildStepsPath(t), stepID), &step); err != nil {
+			if isNotFoundError(err) {
+				continue
+			}
+			return "", false, err
+		}
+		value, ok := propertiesMap(step.Properties)[name]
+		return value, ok, nil
+	}
+	return "", false, nil
+}
+
+func expandInheritedStepParams(raw []interface{}) map[string]map[string]string {
+	out := make(map[string]map[string]string)
+	for _, v := range raw {
+		dt := v.(map[string]interface{})
+		params := make(map[string]string)
+		for name, value := range dt["params"].(map[string]interface{}) {
+			params[name] = value.(string)
+		}
+		out[dt["step_id"].(string)] = params
+	}
+	return out
+}
+
+// flattenInheritedStepParams reads the current values of the properties set in `inherited_step_params`. Steps which
+// are no longer inherited, e.g. as their template has been detached, are dropped.
+func flattenInheritedStepParams(steps []*buildStep, raw []interface{}) []map[string]interface{} {
+	byID := make(map[string]*buildStep, len(steps))
+	for _, s := range steps {
+		byID[s.ID] = s
+	}
+
+	out := make([]map[string]interface{}, 0)
+	for stepID, params := range expandInheritedStepParams(raw) {
+		step, ok := byID[stepID]
+		if !ok || !isInherited(step) {
+			continue
+		}
+		current := propertiesMap(step.Properties)
+		values := make(map[string]interface{})
+		for name := range params {
+			if v, ok := current[name]; ok {
+				values[name] = v
+			}
+		}
+		out = append(out, map[string]interface{}{
+			"step_id": stepID,
+			"params":  values,
+		})
+	}
+	return out
+}
@@ -0,0 +1,57 @@
+package teamcity
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// buildConfigPause is the paused state of a Build Configuration, which go-teamcity doesn't support
+type buildConfigPause struct {
+	Paused       bool                `json:"paused"`
+	PauseComment *buildConfigComment `json:"pauseComment,omitempty"`
+}
+
+type buildConfigComment struct {
+	Text string `json:"text"`
+}
+
+func getBuildConfigPause(r *restClient, id string) (*buildConfigPause, error) {
+	var out buildConfigPause
+	if err := r.get(buildConfigPath(id)+"?fields=paused,pauseComment(text)", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// setBuildConfigPaused pauses or resumes a Build Configuration, with a comment shown alongside the paused state
+func setBuildConfigPaused(r *restClient, id string, paused bool, comment string) error {
+	path := buildConfigPath(id) + "/paused"
+	if comment != "" {
+		path += "?comment=" + url.QueryEscape(comment)
+	}
+	return r.putText(path, strconv.FormatBool(paused))
+}
+
+// flattenBuildConfigPause sets `paused` and `paused_comment`, which only has a value while the Build Configuration is
+// paused
+func flattenBuildConfigPause(d *schema.ResourceData, dt *buildConfigPause) error {
+	var comment string
+	if dt.Paused && dt.PauseComment != nil {
+		comment = dt.PauseComment.Text
+	}
+	if err := d.Set("paused", dt.Paused); err != nil {
+		return err
+	}
+	return d.Set("paused_comment", comment)
+}
+
+func resourceBuildConfigPausedDiff(_ context.Context, diff *schema.ResourceDiff, _ interface{}) error {
+	if diff.Get("paused_comment").(string) != "" && !diff.Get("paused").(bool) {
+		return fmt.Errorf("`paused_comment` can only be set when `paused` is true")
+	}
+	return nil
+}
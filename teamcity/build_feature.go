@@ -0,0 +1,66 @@
+package teamcity
+
+import (
+	"fmt"
+	"strings"
+
+	api "github.com/cvbarros/go-teamcity/teamcity"
+)
+
+// TeamCity build feature types which go-teamcity doesn't support
+const (
+	featureTypeFailureOnMessage = "BuildFailureOnMessage"
+	featureTypeFailureOnMetric  = "BuildFailureOnMetric"
+)
+
+// buildFeature is a build feature as represented by the TeamCity REST API, which includes the failure conditions
+// of a Build Configuration
+type buildFeature struct {
+	ID         string          `json:"id,omitempty"`
+	Type       string          `json:"type"`
+	Disabled   *bool           `json:"disabled,omitempty"`
+	Properties *api.Properties `json:"properties,omitempty"`
+}
+
+func buildFeaturesPath(buildConfigID string) string {
+	return fmt.Sprintf("buildTypes/%s/features", api.LocatorID(buildConfigID))
+}
+
+func getBuildFeature(r *restClient, buildConfigID string, featureID string) (*buildFeature, error) {
+	var out buildFeature
+	if err := r.get(fmt.Sprintf("%s/%s", buildFeaturesPath(buildConfigID), featureID), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func addBuildFeature(r *restClient, buildConfigID string, feature *buildFeature) (*buildFeature, error) {
+	var out buildFeature
+	if err := r.post(buildFeaturesPath(buildConfigID), feature, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func deleteBuildFeature(r *restClient, buildConfigID string, featureID string) error {
+	return r.delete(fmt.Sprintf("%s/%s", buildFeaturesPath(buildConfigID), featureID))
+}
+
+type BuildFailureConditionId struct {
+	BuildConfigID string
+	ConditionID   string
+}
+
+func ParseBuildFailureConditionID(input string) (*BuildFailureConditionId, error) {
+	// Format: 'BuildConfigID|ConditionID'
+	segments := strings.Split(input, "|")
+	if len(segments) != 2 {
+		return nil, fmt.Errorf("Expected 2 segments but got %d", len(segments))
+	}
+
+	id := BuildFailureConditionId{
+		BuildConfigID: segments[0],
+		ConditionID:   segments[1],
+	}
+	return &id, nil
+}
@@ -0,0 +1,513 @@
+package teamcity
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/cvbarros/terraform-provider-teamcity/internal/buildsteps"
+)
+
+// TeamCity runner types which go-teamcity doesn't support
+const (
+	stepTypeGradle       = "gradle-runner"
+	stepTypeMaven        = "Maven2"
+	stepTypeDotnet       = "dotnet"
+	stepTypeDotnetLegacy = "dotnet.cli"
+	stepTypeDocker       = "DockerCommand"
+	stepTypeKotlinScript = "kotlinScript"
+)
+
+// Properties of every step, managed by the `execute_mode` and `condition` attributes
+const (
+	stepPropertyExecuteMode = "teamcity.step.mode"
+	stepPropertyConditions  = "teamcity.step.conditions"
+)
+
+// stepExecuteModes are the values of `execute_mode`
+var stepExecuteModes = []string{
+	api.StepExecuteModeDefault,
+	api.StepExecuteModeOnlyIfBuildIsSuccessful,
+	api.StepExecuteModeEvenWhenFailed,
+	api.StepExecuteAlways,
+}
+
+// defaultKotlinPath is the Kotlin compiler used by kotlin_script steps when `kotlin_path` isn't set
+const defaultKotlinPath = "%teamcity.tool.kotlin.compiler.DEFAULT%"
+
+// featureDotnetRunner is the .NET runner replacing the .NET CLI one, which `dotnet` steps are created with when supported
+var featureDotnetRunner = serverFeature{description: "the .NET runner", major: 2020, minor: 2}
+
+// buildStep is a build step as represented by the TeamCity REST API. Steps are managed through the REST API directly,
+// as go-teamcity only supports some runners and fails to read the steps of a Build Configuration using any other.
+type buildStep struct {
+	ID         string          `json:"id,omitempty"`
+	Name       string          `json:"name,omitempty"`
+	Type       string          `json:"type"`
+	Properties *api.Properties `json:"properties,omitempty"`
+	Disabled   *bool           `json:"disabled,omitempty"`
+	Inherited  *bool           `json:"inherited,omitempty"`
+}
+
+type buildSteps struct {
+	Count int          `json:"count"`
+	Items []*buildStep `json:"step"`
+}
+
+func buildStepsPath(buildConfigID string) string {
+	return fmt.Sprintf("buildTypes/%s/steps", api.LocatorID(buildConfigID))
+}
+
+func getBuildSteps(r *restClient, buildConfigID string) ([]*buildStep, error) {
+	var out buildSteps
+	if err := r.get(buildStepsPath(buildConfigID), &out); err != nil {
+		return nil, err
+	}
+	return out.Items, nil
+}
+
+func addBuildStep(r *restClient, buildConfigID string, step *buildStep) (*buildStep, error) {
+	var out buildStep
+	if err := r.post(buildStepsPath(buildConfigID), step, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func updateBuildStep(r *restClient, buildConfigID string, step *buildStep) (*buildStep, error) {
+	var out buildStep
+	if err := r.put(fmt.Sprintf("%s/%s", buildStepsPath(buildConfigID), step.ID), step, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// orderBuildSteps replaces the steps a Build Configuration defines itself with the given ones, which keep their IDs,
+// changing the order they run in
+func orderBuildSteps(r *restClient, buildConfigID string, steps []*buildStep) error {
+	return r.put(buildStepsPath(buildConfigID), &buildSteps{Count: len(steps), Items: steps}, nil)
+}
+
+func deleteBuildStep(r *restClient, buildConfigID string, stepID string) error {
+	return r.delete(fmt.Sprintf("%s/%s", buildStepsPath(buildConfigID), stepID))
+}
+
+// updateBuildSteps changes the steps of a Build Configuration from the ones in from to the ones in to. A step keeps its
+// ID when it keeps its `step_id` and runner, or its name, so it's updated in place rather than being deleted and created
+// again.
+func updateBuildSteps(r *restClient, buildConfigID string, from, to []*buildStep) error {
+	matches, deleted := buildsteps.Match(buildStepRefs(from), buildStepRefs(to))
+
+	for _, i := range deleted {
+		if err := deleteBuildStep(r, buildConfigID, from[i].ID); err != nil && !isNotFoundError(err) {
+			return err
+		}
+	}
+
+	order := make([]string, len(to))
+	for i, s := range to {
+		if matches[i] == -1 {
+			s.ID = ""
+			created, err := addBuildStep(r, buildConfigID, s)
+			if err != nil {
+				return err
+			}
+			s.ID = created.ID
+		} else {
+			o := from[matches[i]]
+			s.ID = o.ID
+			if !equalBuildSteps(o, s) {
+				if _, err := updateBuildStep(r, buildConfigID, s); err != nil {
+					return err
+				}
+			}
+		}
+		order[i] = s.ID
+	}
+
+	current, err := getBuildSteps(r, buildConfigID)
+	if err != nil {
+		return err
+	}
+	// the steps inherited from templates are left out, as they can't be replaced
+	if buildsteps.OrderChanged(buildStepRefs(current), order) {
+		return orderBuildSteps(r, buildConfigID, to)
+	}
+	return nil
+}
+
+func buildStepRefs(steps []*buildStep) []buildsteps.Step {
+	out := make([]buildsteps.Step, len(steps))
+	for i, s := range steps {
+		out[i] = buildsteps.Step{ID: s.ID, Name: s.Name, Type: s.Type, Inherited: isInherited(s)}
+	}
+	return out
+}
+
+func equalBuildSteps(a, b *buildStep) bool {
+	return a.Type == b.Type &&
+		a.Name == b.Name &&
+		isDisabled(a) == isDisabled(b) &&
+		reflect.DeepEqual(propertiesMap(a.Properties), propertiesMap(b.Properties))
+}
+
+func isDisabled(s *buildStep) bool {
+	return s.Disabled != nil && *s.Disabled
+}
+
+func isInherited(s *buildStep) bool {
+	return s.Inherited != nil && *s.Inherited
+}
+
+func propertiesMap(p *api.Properties) map[string]string {
+	if p == nil {
+		return map[string]string{}
+	}
+	return p.Map()
+}
+
+// newBuildStep converts a step supported by go-teamcity, so the properties it sets are kept as they were
+func newBuildStep(step api.Step) (*buildStep, error) {
+	dt, err := json.Marshal(step)
+	if err != nil {
+		return nil, err
+	}
+
+	var out buildStep
+	if err := json.Unmarshal(dt, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// convertBuildStep converts a step to one supported by go-teamcity
+func convertBuildStep(step *buildStep, out json.Unmarshaler) error {
+	dt, err := json.Marshal(step)
+	if err != nil {
+		return err
+	}
+	return out.UnmarshalJSON(dt)
+}
+
+// stepProperties maps the attributes of a step to the properties of its runner
+type stepProperties map[string]string
+
+var gradleStepProperties = stepProperties{
+	"tasks":       "ui.gradleRunner.gradle.tasks.names",
+	"build_file":  "ui.gradleRunner.gradle.build.file",
+	"use_wrapper": "ui.gradleRUnner.gradle.wrapper.useWrapper",
+	"jdk_home":    "target.jdk.home",
+	"args":        "ui.gradleRunner.additional.gradle.cmd.params",
+}
+
+var mavenStepProperties = stepProperties{
+	"goals":      "goals",
+	"build_file": "pomLocation",
+	"jdk_home":   "target.jdk.home",
+	"args":       "runnerArgs",
+}
+
+var dotnetStepProperties = stepProperties{
+	"command":       "command",
+	"projects":      "paths",
+	"configuration": "configuration",
+	"args":          "args",
+}
+
+// dotnetLegacyStepProperties maps the attributes of `dotnet` steps to the properties of the .NET CLI runner
+var dotnetLegacyStepProperties = stepProperties{
+	"command":       "dotnet-command",
+	"projects":      "dotnet-paths",
+	"configuration": "dotnet-build-config",
+	"args":          "dotnet-args",
+}
+
+var dockerStepProperties = stepProperties{
+	"command":      "docker.command.type",
+	"sub_command":  "docker.sub.command",
+	"file":         "dockerfile.path",
+	"code":         "dockerfile.content",
+	"context_dir":  "dockerfile.contextDir",
+	"image_names":  "docker.image.namesAndTags",
+	"platform":     "dockerImage.platform",
+	"remove_image": "docker.push.remove.image",
+	"args":         "docker.args",
+}
+
+var kotlinScriptStepProperties = stepProperties{
+	"file":        "scriptFile",
+	"code":        "scriptContent",
+	"args":        "kotlinArgs",
+	"kotlin_path": "kotlinPath",
+	"jdk_home":    "target.jdk.home",
+}
+
+// stepTypeAttributes lists the attributes of a step supported by each type, besides `step_id`, `type`, `name`,
+// `enabled`, `execute_mode` and `condition`
+var stepTypeAttributes = map[string][]string{
+	"powershell":    {"file", "args", "code"},
+	"cmd_line":      {"file", "args", "code"},
+	"gradle":        gradleStepProperties.attributes(),
+	"maven":         mavenStepProperties.attributes(),
+	"dotnet":        dotnetStepProperties.attributes(),
+	"docker":        dockerStepProperties.attributes(),
+	"kotlin_script": kotlinScriptStepProperties.attributes(),
+	"generic":       {"parameters", "runner_type"},
+}
+
+// stepTypeCommands lists the values of `command` supported by each type
+var stepTypeCommands = map[string][]string{
+	"dotnet": {"build", "clean", "custom", "msbuild", "nuget-delete", "nuget-push", "pack", "publish", "restore", "run", "test", "vstest"},
+	"docker": {"build", "push", "other"},
+}
+
+func (p stepProperties) attributes() []string {
+	out := make([]string, 0, len(p))
+	for k := range p {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func (p stepProperties) expand(runnerType string, dt map[string]interface{}) *buildStep {
+	props := api.NewPropertiesEmpty()
+	for attribute, property := range p {
+		switch v := dt[attribute].(type) {
+		case string:
+			if v != "" {
+				props.AddOrReplaceValue(property, v)
+			}
+		case bool:
+			if v {
+				props.AddOrReplaceValue(property, strconv.FormatBool(v))
+			}
+		}
+	}
+
+	step := &buildStep{
+		Type:       runnerType,
+		Properties: props,
+	}
+	if v, ok := dt["name"]; ok {
+		step.Name = v.(string)
+	}
+	if v, ok := dt["step_id"]; ok {
+		step.ID = v.(string)
+	}
+	return step
+}
+
+func (p stepProperties) flatten(stepType string, s *buildStep, boolAttributes ...string) map[string]interface{} {
+	m := make(map[string]interface{})
+	if s.Properties != nil {
+		for attribute, property := range p {
+			if v, ok := s.Properties.GetOk(property); ok && v != "" {
+				m[attribute] = v
+			}
+		}
+	}
+	for _, attribute := range boolAttributes {
+		if v, ok := m[attribute]; ok {
+			m[attribute] = v == "true"
+		}
+	}
+	if s.Name != "" {
+		m["name"] = s.Name
+	}
+	m["type"] = stepType
+
+	return m
+}
+
+func expandStepGradle(dt map[string]interface{}) (*buildStep, error) {
+	return gradleStepProperties.expand(stepTypeGradle, dt), nil
+}
+
+func expandStepMaven(dt map[string]interface{}) (*buildStep, error) {
+	if v, _ := dt["goals"].(string); v == "" {
+		return nil, fmt.Errorf("`goals` is required for maven steps")
+	}
+	return mavenStepProperties.expand(stepTypeMaven, dt), nil
+}
+
+// expandStepDotnet creates a step for the .NET runner, or for the .NET CLI runner it replaced when the server is known
+// not to support it
+func expandStepDotnet(dt map[string]interface{}, client *Client) (*buildStep, error) {
+	if v, _ := dt["command"].(string); v == "" {
+		return nil, fmt.Errorf("`command` is required for dotnet steps")
+	}
+
+	if client.lacksServerFeature(featureDotnetRunner) {
+		return dotnetLegacyStepProperties.expand(stepTypeDotnetLegacy, dt), nil
+	}
+	return dotnetStepProperties.expand(stepTypeDotnet, dt), nil
+}
+
+func expandStepDocker(dt map[string]interface{}) (*buildStep, error) {
+	command, _ := dt["command"].(string)
+	if command == "" {
+		return nil, fmt.Errorf("`command` is required for docker steps")
+	}
+	if subCommand, _ := dt["sub_command"].(string); command == "other" && subCommand == "" {
+		return nil, fmt.Errorf("`sub_command` is required for docker steps running `other` commands")
+	}
+
+	step := dockerStepProperties.expand(stepTypeDocker, dt)
+	if command == "build" {
+		// the Dockerfile is either read from a file or specified inline
+		if v, _ := dt["code"].(string); v != "" {
+			step.Properties.AddOrReplaceValue("dockerfile.source", "CONTENT")
+		} else {
+			step.Properties.AddOrReplaceValue("dockerfile.source", "PATH")
+		}
+	}
+	return step, nil
+}
+
+func expandStepKotlinScript(dt map[string]interface{}) (*buildStep, error) {
+	file, _ := dt["file"].(string)
+	code, _ := dt["code"].(string)
+	if (file == "") == (code == "") {
+		return nil, fmt.Errorf("exactly one of `file` or `code` is required for kotlin_script steps")
+	}
+
+	step := kotlinScriptStepProperties.expand(stepTypeKotlinScript, dt)
+	if code != "" {
+		step.Properties.AddOrReplaceValue("scriptType", "customScript")
+	} else {
+		step.Properties.AddOrReplaceValue("scriptType", "file")
+	}
+	if v, _ := dt["kotlin_path"].(string); v == "" {
+		step.Properties.AddOrReplaceValue("kotlinPath", defaultKotlinPath)
+	}
+	return step, nil
+}
+
+// expandStepGeneric creates a step for any runner, using the parameters of the configuration as its properties
+func expandStepGeneric(dt map[string]interface{}) (*buildStep, error) {
+	runnerType, _ := dt["runner_type"].(string)
+	if runnerType == "" {
+		return nil, fmt.Errorf("`runner_type` is required for generic steps")
+	}
+
+	props := api.NewPropertiesEmpty()
+	if v, ok := dt["parameters"].(map[string]interface{}); ok {
+		for name, value := range v {
+			props.AddOrReplaceValue(name, value.(string))
+		}
+	}
+
+	step := &buildStep{
+		Type:       runnerType,
+		Properties: props,
+	}
+	if v, ok := dt["name"]; ok {
+		step.Name = v.(string)
+	}
+	if v, ok := dt["step_id"]; ok {
+		step.ID = v.(string)
+	}
+	return step, nil
+}
+
+func flattenBuildStepGradle(s *buildStep) map[string]interface{} {
+	return gradleStepProperties.flatten("gradle", s, "use_wrapper")
+}
+
+func flattenBuildStepMaven(s *buildStep) map[string]interface{} {
+	return mavenStepProperties.flatten("maven", s)
+}
+
+func flattenBuildStepDotnet(s *buildStep) map[string]interface{} {
+	if s.Type == stepTypeDotnetLegacy {
+		return dotnetLegacyStepProperties.flatten("dotnet", s)
+	}
+	return dotnetStepProperties.flatten("dotnet", s)
+}
+
+func flattenBuildStepDocker(s *buildStep) map[string]interface{} {
+	return dockerStepProperties.flatten("docker", s, "remove_image")
+}
+
+func flattenBuildStepKotlinScript(s *buildStep) map[string]interface{} {
+	m := kotlinScriptStepProperties.flatten("kotlin_script", s)
+	if m["kotlin_path"] == defaultKotlinPath {
+		delete(m, "kotlin_path")
+	}
+	return m
+}
+
+func flattenBuildStepGeneric(s *buildStep) map[string]interface{} {
+	parameters := make(map[string]interface{})
+	for name, value := range propertiesMap(s.Properties) {
+		// managed by the attributes common to every step
+		if name == stepPropertyExecuteMode || name == stepPropertyConditions {
+			continue
+		}
+		parameters[name] = value
+	}
+
+	m := map[string]interface{}{
+		"type":        "generic",
+		"runner_type": s.Type,
+		"parameters":  parameters,
+	}
+	if s.Name != "" {
+		m["name"] = s.Name
+	}
+	return m
+}
+
+// validateBuildStep checks that a step only sets the attributes and commands supported by its type
+func validateBuildStep(index int, dt map[string]interface{}) error {
+	stepType, _ := dt["type"].(string)
+	supported, ok := stepTypeAttributes[stepType]
+	if !ok {
+		return nil
+	}
+
+	if commands, ok := stepTypeCommands[stepType]; ok {
+		if v, _ := dt["command"].(string); v != "" && !containsString(commands, v) {
+			return fmt.Errorf("step.%d: `command` must be one of %v for %s steps, got: %s", index, commands, stepType, v)
+		}
+	}
+
+	for attribute := range dt {
+		switch attribute {
+		case "step_id", "type", "name", "enabled", "execute_mode", "condition":
+			continue
+		}
+		if isZeroValue(dt[attribute]) || containsString(supported, attribute) {
+			continue
+		}
+		return fmt.Errorf("step.%d: `%s` isn't supported by %s steps, supported attributes are: %v", index, attribute, stepType, supported)
+	}
+	return nil
+}
+
+func isZeroValue(v interface{}) bool {
+	switch value := v.(type) {
+	case nil:
+		return true
+	case string:
+		return value == ""
+	case bool:
+		return !value
+	case map[string]interface{}:
+		return len(value) == 0
+	}
+	return false
+}
+
+func containsString(items []string, v string) bool {
+	for _, item := range items {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package teamcity
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// stepCondition is a condition on a parameter, which must be met for a step to run
+type stepCondition struct {
+	XMLName xml.Name
+	Name    string `xml:"name,attr"`
+	Value   string `xml:"value,attr,omitempty"`
+}
+
+// stepConditions is the representation of the conditions of a step in its properties, where all conditions must be met
+type stepConditions struct {
+	XMLName xml.Name        `xml:"and"`
+	Items   []stepCondition `xml:",any"`
+}
+
+func formatStepConditions(conditions []stepCondition) (string, error) {
+	dt, err := xml.Marshal(&stepConditions{Items: conditions})
+	if err != nil {
+		return "", fmt.Errorf("error formatting step conditions: %w", err)
+	}
+	return string(dt), nil
+}
+
+func parseStepConditions(v string) ([]stepCondition, error) {
+	var out stepConditions
+	if err := xml.Unmarshal([]byte(v), &out); err != nil {
+		return nil, fmt.Errorf("error parsing step conditions %q: %w", v, err)
+	}
+	return out.Items, nil
+}
+
+func expandStepConditions(raw []interface{}) []stepCondition {
+	out := make([]stepCondition, 0, len(raw))
+	for _, r := range raw {
+		dt := r.(map[string]interface{})
+		out = append(out, stepCondition{
+			XMLName: xml.Name{Local: dt["type"].(string)},
+			Name:    dt["name"].(string),
+			Value:   dt["value"].(string),
+		})
+	}
+	return out
+}
+
+func flattenStepConditions(conditions []stepCondition) []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(conditions))
+	for _, c := range conditions {
+		out = append(out, map[string]interface{}{
+			"type":  c.XMLName.Local,
+			"name":  c.Name,
+			"value": c.Value,
+		})
+	}
+	return out
+}
@@ -0,0 +1,206 @@
+package teamcity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/cvbarros/terraform-provider-teamcity/internal/cache"
+	"github.com/cvbarros/terraform-provider-teamcity/internal/mutexkv"
+	"github.com/cvbarros/terraform-provider-teamcity/internal/transport"
+)
+
+// Client is passed to resources and data sources as the provider meta. As go-teamcity doesn't accept a context,
+// it builds API clients bound to the context of each operation, so requests are cancelled along with the operation.
+type Client struct {
+	address     string
+	auth        api.Auth
+	credentials credentials
+	httpClient  *http.Client
+
+	// api isn't bound to any context, and is only used where no context is available
+	api *api.Client
+
+	// serverVersion is nil when the version of the server couldn't be detected
+	serverVersion *ServerVersion
+
+	// buildTypes caches Build Configurations, which are otherwise retrieved by each of their sub-resources
+	buildTypes *cache.Cache
+
+	// locks serializes modifications of the same Build Configuration or Project
+	locks *mutexkv.MutexKV
+}
+
+func newClient(address string, creds credentials, httpClient *http.Client) (*Client, error) {
+	auth := api.BasicAuth(creds.username, creds.password)
+	if creds.token != "" {
+		auth = api.TokenAuth(creds.token)
+	}
+
+	apiClient, err := api.NewClientWithAddress(auth, address, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		address:     address,
+		auth:        auth,
+		credentials: creds,
+		httpClient:  httpClient,
+		api:         apiClient,
+		buildTypes:  cache.New(),
+		locks:       mutexkv.New(),
+	}, nil
+}
+
+// API returns a TeamCity API client whose requests are bound to ctx
+func (c *Client) API(ctx context.Context) *api.Client {
+	httpClient := &http.Client{
+		Transport: transport.NewContextTransport(c.httpClient.Transport, ctx),
+	}
+
+	apiClient, err := api.NewClientWithAddress(c.auth, c.address, httpClient)
+	if err != nil {
+		// the address and auth were already validated when building c.api, so this isn't expected to happen
+		return c.api
+	}
+	return apiClient
+}
+
+// cachedBuildType is a Build Configuration as cached by Client
+type cachedBuildType struct {
+	buildType *api.BuildType
+
+	// inheritable holds the items of each of the inheritedCollections, which go-teamcity doesn't read
+	inheritable map[string]map[string]*inheritableItem
+}
+
+// getBuildType returns the Build Configuration with the given ID. Responses are shared between concurrent callers and
+// cached until invalidateBuildType is called, so the returned Build Configuration mustn't be modified.
+func (c *Client) getBuildType(ctx context.Context, id string) (*api.BuildType, error) {
+	v, err := c.getCachedBuildType(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return v.buildType, nil
+}
+
+// getBuildTypeInheritableItems returns the items of the Build Configuration with the given ID which can be inherited
+// from templates, by collection. Like getBuildType, it reads them from the cached Build Configuration.
+func (c *Client) getBuildTypeInheritableItems(ctx context.Context, id string) (map[string]map[string]*inheritableItem, error) {
+	v, err := c.getCachedBuildType(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return v.inheritable, nil
+}
+
+func (c *Client) getCachedBuildType(ctx context.Context, id string) (*cachedBuildType, error) {
+	v, err := c.buildTypes.Get(ctx, id, func(ctx context.Context) (interface{}, error) {
+		var payload json.RawMessage
+		if err := c.rest(ctx).get(buildConfigPath(id), &payload); err != nil {
+			return nil, err
+		}
+		var dt api.BuildType
+		if err := json.Unmarshal(payload, &dt); err != nil {
+			return nil, err
+		}
+		// as go-teamcity does, only the parameters defined by the Build Configuration itself are kept
+		if dt.Parameters != nil {
+			dt.Parameters = dt.Parameters.NonInherited()
+		}
+		inheritable, err := readInheritableItems(payload)
+		if err != nil {
+			return nil, err
+		}
+		return &cachedBuildType{buildType: &dt, inheritable: inheritable}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*cachedBuildType), nil
+}
+
+// invalidateBuildType removes the Build Configuration with the given ID from the cache, and should be called when it,
+// or any of its sub-resources, are modified
+func (c *Client) invalidateBuildType(id string) {
+	c.buildTypes.Invalidate(id)
+}
+
+// invalidateBuildTypes removes every Build Configuration from the cache, e.g. when a Project containing them is deleted
+func (c *Client) invalidateBuildTypes() {
+	c.buildTypes.InvalidateAll()
+}
+
+// lockBuildType serializes modifications of a Build Configuration and its sub-resources, since TeamCity may reject or
+// lose concurrent modifications of the same Build Configuration. It waits for the lock until ctx is done, and returns a
+// function which invalidates the cached Build Configuration and releases the lock, e.g.:
+//
+//	unlock, err := meta.(*Client).lockBuildType(ctx, buildConfigID)
+//	if err != nil {
+//		return diag.FromErr(err)
+//	}
+//	defer unlock()
+func (c *Client) lockBuildType(ctx context.Context, id string) (func(), error) {
+	key := fmt.Sprintf("buildType/%s", id)
+	if err := c.locks.LockContext(ctx, key); err != nil {
+		return nil, err
+	}
+	return func() {
+		c.invalidateBuildType(id)
+		c.locks.Unlock(key)
+	}, nil
+}
+
+// lockProject serializes modifications of a Project's features and Agent Pool assignments. It waits for the lock until
+// ctx is done, and returns a function which releases the lock.
+func (c *Client) lockProject(ctx context.Context, id string) (func(), error) {
+	key := fmt.Sprintf("project/%s", id)
+	if err := c.locks.LockContext(ctx, key); err != nil {
+		return nil, err
+	}
+	return func() {
+		c.locks.Unlock(key)
+	}, nil
+}
+
+// ServerVersion returns the version of the TeamCity server, or nil when it couldn't be detected
+func (c *Client) ServerVersion() *ServerVersion {
+	return c.serverVersion
+}
+
+// detectServerVersion retrieves the version of the TeamCity server, so resources can refuse or adapt to features
+// the server doesn't support
+func (c *Client) detectServerVersion(ctx context.Context) error {
+	var server api.Server
+	if err := c.rest(ctx).get("server", &server); err != nil {
+		return err
+	}
+
+	version, err := ParseServerVersion(server.Version)
+	if err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] Connected to TeamCity %s", version)
+
+	c.serverVersion = version
+	return nil
+}
+
+// requireServerFeature returns an error when the server is known not to support feature. As older versions of the
+// provider didn't check this, it's permissive when the version of the server couldn't be detected.
+func (c *Client) requireServerFeature(feature serverFeature) error {
+	if !c.lacksServerFeature(feature) {
+		return nil
+	}
+	return fmt.Errorf("%s requires TeamCity %d.%d or later, but the server at %s is running TeamCity %s", feature.description, feature.major, feature.minor, c.address, c.serverVersion)
+}
+
+// lacksServerFeature returns whether the server is known not to support feature, which it isn't when the version of
+// the server couldn't be detected
+func (c *Client) lacksServerFeature(feature serverFeature) bool {
+	return c.serverVersion != nil && !c.serverVersion.AtLeast(feature.major, feature.minor)
+}
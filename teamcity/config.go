@@ -1,9 +1,17 @@
 package teamcity
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
 	"net/http"
+	"os"
+	"strings"
+	"time"
 
-	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/cvbarros/terraform-provider-teamcity/internal/transport"
+	"github.com/hashicorp/go-cleanhttp"
 )
 
 // Config Used to configure an api client for TeamCity
@@ -12,18 +20,103 @@ type Config struct {
 	Token    string
 	Username string
 	Password string
+
+	// MaxRetries is the number of times a request failing with a transient error is retried
+	MaxRetries int
+	// MaxRetryWait caps the time waited between two attempts of a request
+	MaxRetryWait time.Duration
+
+	// CACertFile is the path to a PEM-encoded CA bundle used to verify the server certificate
+	CACertFile string
+	// CACertPEM is a PEM-encoded CA bundle used to verify the server certificate
+	CACertPEM string
+	// ClientCert is the PEM-encoded client certificate (or a path to it) used for mutual TLS
+	ClientCert string
+	// ClientKey is the PEM-encoded private key (or a path to it) matching ClientCert
+	ClientKey string
+	// InsecureSkipVerify disables the verification of the server certificate
+	InsecureSkipVerify bool
 }
 
-// Client Returns a new TeamCity api client configured with this instance parameters
-func (c *Config) Client() (*api.Client, error) {
+// Client Returns a new TeamCity client configured with this instance parameters.
+// Requests which aren't bound to a context are logged using ctx.
+func (c *Config) Client(ctx context.Context) (*Client, error) {
+	baseTransport := http.DefaultTransport
+	if c.hasTLSConfig() {
+		tlsConfig, err := c.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		// `http.DefaultTransport` is replaced by a logging wrapper in go-teamcity, so it can't be cloned
+		t := cleanhttp.DefaultPooledTransport()
+		t.TLSClientConfig = tlsConfig
+		baseTransport = t
+	}
+
 	// `http.DefaultClient` doesn't configure a proxy by default - this does
 	httpClient := &http.Client{
-		Transport: http.DefaultTransport,
+		Transport: transport.NewRetryTransport(transport.NewLoggingTransport(baseTransport, ctx), c.MaxRetries, c.MaxRetryWait),
+	}
+
+	return newClient(c.Address, credentials{token: c.Token, username: c.Username, password: c.Password}, httpClient)
+}
+
+func (c *Config) hasTLSConfig() bool {
+	return c.CACertFile != "" || c.CACertPEM != "" || c.ClientCert != "" || c.ClientKey != "" || c.InsecureSkipVerify
+}
+
+func (c *Config) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	caCert := []byte(c.CACertPEM)
+	if c.CACertFile != "" {
+		v, err := os.ReadFile(c.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA certificate file %q: %s", c.CACertFile, err)
+		}
+		caCert = v
+	}
+	if len(caCert) > 0 {
+		// the custom CA is trusted in addition to the system ones, so public endpoints keep working
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("error loading CA certificate: no valid PEM-encoded certificates found")
+		}
+		tlsConfig.RootCAs = pool
 	}
 
-	if c.Token != "" {
-		return api.NewClientWithAddress(api.TokenAuth(c.Token), c.Address, httpClient)
+	if c.ClientCert != "" || c.ClientKey != "" {
+		if c.ClientCert == "" || c.ClientKey == "" {
+			return nil, fmt.Errorf("both `client_cert` and `client_key` must be specified to use a client certificate")
+		}
+		certPEM, err := readPEMOrFile(c.ClientCert)
+		if err != nil {
+			return nil, fmt.Errorf("error reading client certificate: %s", err)
+		}
+		keyPEM, err := readPEMOrFile(c.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("error reading client key: %s", err)
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %s", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
 	}
 
-	return api.NewClientWithAddress(api.BasicAuth(c.Username, c.Password), c.Address, httpClient)
+	return tlsConfig, nil
+}
+
+// readPEMOrFile returns v if it holds PEM-encoded content, otherwise reads the file at path v
+func readPEMOrFile(v string) ([]byte, error) {
+	if strings.HasPrefix(strings.TrimSpace(v), "-----BEGIN") {
+		return []byte(v), nil
+	}
+	return os.ReadFile(v)
 }
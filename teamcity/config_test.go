@@ -0,0 +1,192 @@
+package teamcity_test
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cvbarros/terraform-provider-teamcity/teamcity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestConfigClient_CustomCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	cases := map[string]struct {
+		config  teamcity.Config
+		success bool
+	}{
+		"default trust store": {
+			config:  teamcity.Config{Address: server.URL, Token: "token"},
+			success: false,
+		},
+		"custom CA": {
+			config:  teamcity.Config{Address: server.URL, Token: "token", CACertPEM: string(caCert)},
+			success: true,
+		},
+		"insecure": {
+			config:  teamcity.Config{Address: server.URL, Token: "token", InsecureSkipVerify: true},
+			success: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			client, err := c.config.Client(context.Background())
+			if err != nil {
+				t.Fatalf("error building client: %s", err)
+			}
+
+			resp, err := client.API(context.Background()).HTTPClient.Get(server.URL)
+			if c.success {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				resp.Body.Close()
+			} else if err == nil {
+				resp.Body.Close()
+				t.Fatalf("expected a certificate verification error")
+			}
+		})
+	}
+}
+
+func TestConfigClient_InvalidCA(t *testing.T) {
+	config := teamcity.Config{Address: "https://teamcity.example.com", Token: "token", CACertPEM: "not a certificate"}
+	if _, err := config.Client(context.Background()); err == nil {
+		t.Fatalf("expected an error for an invalid CA certificate")
+	}
+}
+
+func TestConfigClient_ClientCertWithoutKey(t *testing.T) {
+	config := teamcity.Config{Address: "https://teamcity.example.com", Token: "token", ClientCert: "/tmp/client.pem"}
+	if _, err := config.Client(context.Background()); err == nil {
+		t.Fatalf("expected an error when client_key is missing")
+	}
+}
+
+func TestProviderConfigure_CustomCA(t *testing.T) {
+	var requests []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/app/rest/server":
+			fmt.Fprint(w, `{"version":"2020.1 (build 78475)","buildNumber":"78475","webUrl":"https://teamcity.example.com"}`)
+		case "/app/rest/buildTypes/id:Bt":
+			fmt.Fprint(w, `{"id":"Bt","name":"Build","projectId":"Project","settings":{"count":0,"property":[]},"steps":{"count":0,"step":[]},"vcs-root-entries":{"count":0,"vcs-root-entry":[]}}`)
+		case "/app/rest/buildTypes/id:Bt/agent-requirements", "/app/rest/buildTypes/id:Bt/agent-requirements/RQ_1":
+			fmt.Fprint(w, `{"id":"RQ_1","type":"equals","properties":{"count":2,"property":[{"name":"property-name","value":"env.OS"},{"name":"property-value","value":"Linux"}]}}`)
+		case "/app/rest/buildTypes/id:Bt/snapshot-dependencies", "/app/rest/buildTypes/id:Bt/snapshot-dependencies/Source":
+			fmt.Fprint(w, `{"id":"Source","type":"snapshot_dependency","source-buildType":{"id":"Source"}}`)
+		case "/app/rest/buildTypes/id:Bt/artifact-dependencies", "/app/rest/buildTypes/id:Bt/artifact-dependencies/ARTIFACT_DEPENDENCY_1":
+			fmt.Fprint(w, `{"id":"ARTIFACT_DEPENDENCY_1","type":"artifact_dependency","source-buildType":{"id":"Source"},"properties":{"count":4,"property":[{"name":"cleanDestinationDirectory","value":"false"},{"name":"pathRules","value":"out/**"},{"name":"revisionName","value":"lastSuccessful"},{"name":"revisionValue","value":"latest.lastSuccessful"}]}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, "Responding with error, status code: 404 (Not Found).")
+		}
+	}))
+	defer server.Close()
+
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	provider := teamcity.Provider()
+	diags := provider.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"address":     server.URL,
+		"token":       "token",
+		"ca_cert_pem": string(caCert),
+	}))
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diagnostics configuring the provider: %+v", diags)
+	}
+
+	client := provider.Meta().(*teamcity.Client)
+	if v := client.ServerVersion(); v == nil || v.Major != 2020 || v.Minor != 1 {
+		t.Fatalf("expected the server version to be detected, got %v", v)
+	}
+
+	ds := provider.DataSourcesMap["teamcity_server"]
+	d := ds.TestResourceData()
+	if diags := ds.ReadContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("unexpected error reading the server: %+v", diags)
+	}
+	if v := d.Get("version").(string); v != "2020.1 (build 78475)" {
+		t.Fatalf("expected the server version to be read, got %q", v)
+	}
+
+	// the Build Configuration is looked up before creating the agent requirement
+	res := provider.ResourcesMap["teamcity_agent_requirement"]
+	d = res.TestResourceData()
+	d.Set("build_config_id", "Missing")
+	diags = res.CreateContext(context.Background(), d, client)
+	if !diags.HasError() || diags[0].Summary != "invalid build_config_id 'Missing'" {
+		t.Fatalf("expected the build configuration not to be found, got %+v", diags)
+	}
+	if requests[len(requests)-1] != "/app/rest/buildTypes/id:Missing" {
+		t.Fatalf("expected the build configuration to be requested, got %v", requests)
+	}
+
+	// sub-resources of Build Configurations are created and read through the configured client as well
+	d = res.TestResourceData()
+	d.Set("build_config_id", "Bt")
+	d.Set("condition", "equals")
+	d.Set("name", "env.OS")
+	d.Set("value", "Linux")
+	if diags := res.CreateContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("unexpected error creating the agent requirement: %+v", diags)
+	}
+	if d.Id() != "RQ_1" || d.Get("name").(string) != "env.OS" || d.Get("value").(string) != "Linux" {
+		t.Fatalf("expected the agent requirement to be read, got %q: %v", d.Id(), d.State())
+	}
+
+	res = provider.ResourcesMap["teamcity_snapshot_dependency"]
+	d = res.TestResourceData()
+	d.Set("build_config_id", "Bt")
+	d.Set("source_build_config_id", "Source")
+	if diags := res.CreateContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("unexpected error creating the snapshot dependency: %+v", diags)
+	}
+	if d.Id() != "Source" || d.Get("source_build_config_id").(string) != "Source" {
+		t.Fatalf("expected the snapshot dependency to be read, got %q: %v", d.Id(), d.State())
+	}
+
+	res = provider.ResourcesMap["teamcity_artifact_dependency"]
+	d = res.TestResourceData()
+	d.Set("build_config_id", "Bt")
+	d.Set("source_build_config_id", "Source")
+	d.Set("path_rules", []interface{}{"out/**"})
+	d.Set("dependency_revision", "lastSuccessful")
+	if diags := res.CreateContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("unexpected error creating the artifact dependency: %+v", diags)
+	}
+	if d.Id() != "ARTIFACT_DEPENDENCY_1" || d.Get("path_rules.0").(string) != "out/**" {
+		t.Fatalf("expected the artifact dependency to be read, got %q: %v", d.Id(), d.State())
+	}
+
+	for _, path := range []string{
+		"/app/rest/buildTypes/id:Bt/agent-requirements/RQ_1",
+		"/app/rest/buildTypes/id:Bt/snapshot-dependencies/Source",
+		"/app/rest/buildTypes/id:Bt/artifact-dependencies/ARTIFACT_DEPENDENCY_1",
+	} {
+		if !containsString(requests, path) {
+			t.Fatalf("expected %s to be requested, got %v", path, requests)
+		}
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package teamcity
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultCredentialsFile is the file profiles are read from when `credentials_file` isn't set
+const DefaultCredentialsFile = "~/.teamcity/credentials"
+
+// CredentialsProfile is a named set of credentials for a TeamCity server, read from a credentials file such as:
+//
+//	[staging]
+//	address = https://teamcity-staging.example.com
+//	token   = eyJ0eXAiOiAiVENWMiJ9...
+//
+//	[production]
+//	address  = https://teamcity.example.com
+//	username = terraform
+//	password = hunter2
+type CredentialsProfile struct {
+	Address  string
+	Token    string
+	Username string
+	Password string
+}
+
+// LoadCredentialsProfile reads the profile named name from the credentials file at path. A leading `~` in path is
+// expanded to the home directory of the current user.
+func LoadCredentialsProfile(path, name string) (*CredentialsProfile, error) {
+	path, err := expandHomeDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading credentials file: %w", err)
+	}
+	defer f.Close()
+
+	var profile *CredentialsProfile
+	var section string
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("%s:%d: invalid profile header %q", path, n, line)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			if section == name {
+				if profile != nil {
+					return nil, fmt.Errorf("%s:%d: profile %q is defined more than once", path, n, name)
+				}
+				profile = &CredentialsProfile{}
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected `key = value` but got %q", path, n, line)
+		}
+		if section != name {
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+		switch key = strings.TrimSpace(key); key {
+		case "address":
+			profile.Address = value
+		case "token":
+			profile.Token = value
+		case "username":
+			profile.Username = value
+		case "password":
+			profile.Password = value
+		default:
+			return nil, fmt.Errorf("%s:%d: unsupported key %q in profile %q", path, n, key, name)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading credentials file: %w", err)
+	}
+
+	if profile == nil {
+		return nil, fmt.Errorf("profile %q was not found in %s", name, path)
+	}
+	if profile.Token != "" && (profile.Username != "" || profile.Password != "") {
+		return nil, fmt.Errorf("profile %q in %s specifies both a `token` and a `username`/`password`, only one may be used", name, path)
+	}
+	return profile, nil
+}
+
+func expandHomeDir(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error expanding %q: %w", path, err)
+	}
+	return filepath.Join(home, path[1:]), nil
+}
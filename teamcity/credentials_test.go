@@ -0,0 +1,149 @@
+package teamcity_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cvbarros/terraform-provider-teamcity/teamcity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const testCredentialsFile = `
+# shared TeamCity servers
+[staging]
+address = https://teamcity-staging.example.com
+token   = staging-token
+
+[production]
+address  = https://teamcity.example.com
+username = terraform
+password = p@ss=word
+`
+
+func TestLoadCredentialsProfile(t *testing.T) {
+	path := writeCredentialsFile(t, testCredentialsFile)
+
+	cases := map[string]teamcity.CredentialsProfile{
+		"staging": {
+			Address: "https://teamcity-staging.example.com",
+			Token:   "staging-token",
+		},
+		"production": {
+			Address:  "https://teamcity.example.com",
+			Username: "terraform",
+			Password: "p@ss=word",
+		},
+	}
+
+	for name, expected := range cases {
+		t.Run(name, func(t *testing.T) {
+			profile, err := teamcity.LoadCredentialsProfile(path, name)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if *profile != expected {
+				t.Fatalf("expected %+v but got %+v", expected, *profile)
+			}
+		})
+	}
+}
+
+func TestLoadCredentialsProfile_Invalid(t *testing.T) {
+	cases := map[string]struct {
+		contents string
+		profile  string
+	}{
+		"missing profile": {
+			contents: testCredentialsFile,
+			profile:  "development",
+		},
+		"unsupported key": {
+			contents: "[staging]\naddres = https://teamcity-staging.example.com\n",
+			profile:  "staging",
+		},
+		"token and username": {
+			contents: "[staging]\ntoken = staging-token\nusername = terraform\n",
+			profile:  "staging",
+		},
+		"invalid line": {
+			contents: "[staging]\ntoken\n",
+			profile:  "staging",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := writeCredentialsFile(t, c.contents)
+			if _, err := teamcity.LoadCredentialsProfile(path, c.profile); err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+
+	if _, err := teamcity.LoadCredentialsProfile(filepath.Join(t.TempDir(), "missing"), "staging"); err == nil {
+		t.Fatalf("expected an error for a missing credentials file")
+	}
+}
+
+func TestProviderConfigure_Profile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer local-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"version":"2020.1 (build 78475)"}`)
+	}))
+	defer server.Close()
+
+	path := writeCredentialsFile(t, testCredentialsFile+"\n[local]\naddress = "+server.URL+"\ntoken = local-token\n\n[tokenonly]\ntoken = other-token\n")
+
+	cases := map[string]struct {
+		config  map[string]interface{}
+		summary string
+	}{
+		"profile": {
+			config: map[string]interface{}{"profile": "local"},
+		},
+		"same address": {
+			config: map[string]interface{}{"profile": "local", "address": server.URL + "/"},
+		},
+		"other address": {
+			config:  map[string]interface{}{"profile": "staging", "address": server.URL},
+			summary: "Error configuring provider: `address` differs from the one of the selected `profile`",
+		},
+		"profile without address": {
+			config:  map[string]interface{}{"profile": "tokenonly", "address": server.URL},
+			summary: "Error configuring provider: the profile doesn't specify an `address`",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			c.config["credentials_file"] = path
+			diags := teamcity.Provider().Configure(context.Background(), terraform.NewResourceConfigRaw(c.config))
+			if c.summary == "" {
+				if len(diags) > 0 {
+					t.Fatalf("unexpected diagnostics: %+v", diags)
+				}
+				return
+			}
+			if !diags.HasError() || diags[0].Summary != c.summary {
+				t.Fatalf("expected an error %q, got %+v", c.summary, diags)
+			}
+		})
+	}
+}
+
+func writeCredentialsFile(t *testing.T, contents string) string {
+	path := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatalf("error writing credentials file: %s", err)
+	}
+	return path
+}
@@ -1,15 +1,16 @@
 package teamcity
 
 import (
+	"context"
 	"fmt"
 
-	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceAgentPool() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceAgentPoolRead,
+		ReadContext: dataSourceAgentPoolRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -23,13 +24,13 @@ func dataSourceAgentPool() *schema.Resource {
 	}
 }
 
-func dataSourceAgentPoolRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func dataSourceAgentPoolRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
 
 	name := d.Get("name").(string)
 	agentPool, err := client.AgentPools.GetByName(name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(fmt.Sprintf("%d", agentPool.Id))
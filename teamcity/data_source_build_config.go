@@ -0,0 +1,203 @@
+package teamcity
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+
+	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// buildConfigSummary is a Build Configuration as read by the data source. It's read through the REST API directly, as
+// go-teamcity doesn't expose the web URL or the paused state.
+type buildConfigSummary struct {
+	ID             string                   `json:"id"`
+	Name           string                   `json:"name"`
+	ProjectID      string                   `json:"projectId"`
+	Description    string                   `json:"description"`
+	TemplateFlag   bool                     `json:"templateFlag"`
+	WebURL         string                   `json:"webUrl"`
+	VcsRootEntries *buildConfigSummaryRoots `json:"vcs-root-entries,omitempty"`
+	buildConfigPause
+}
+
+type buildConfigSummaryRoots struct {
+	Items []*buildConfigSummaryRoot `json:"vcs-root-entry"`
+}
+
+type buildConfigSummaryRoot struct {
+	ID            string `json:"id"`
+	CheckoutRules string `json:"checkout-rules"`
+}
+
+const buildConfigSummaryFields = "id,name,projectId,description,templateFlag,webUrl,paused,pauseComment(text),vcs-root-entries(vcs-root-entry(id,checkout-rules))"
+
+func dataSourceBuildConfig() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceBuildConfigRead,
+		Schema: map[string]*schema.Schema{
+			"build_config_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"build_config_id", "name", "locator"},
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				RequiredWith: []string{"project_id"},
+			},
+			"project_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"locator": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"is_template": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"url": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"paused": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"paused_comment": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"env_params": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"config_params": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"sys_params": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"vcs_root": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"checkout_rules": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceBuildConfigRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	r := meta.(*Client).rest(ctx)
+
+	var attribute, locator, description string
+	if v, ok := d.GetOk("build_config_id"); ok {
+		attribute, locator = "build_config_id", api.LocatorID(v.(string)).String()
+		description = fmt.Sprintf("build configuration %q", v.(string))
+	} else if v, ok := d.GetOk("name"); ok {
+		projectID := d.Get("project_id").(string)
+		attribute, locator = "name", url.PathEscape(fmt.Sprintf("project:(id:%s),name:%s", locatorValue(projectID), locatorValue(v.(string))))
+		description = fmt.Sprintf("build configuration named %q in project %q", v.(string), projectID)
+	} else if v, ok := d.GetOk("locator"); ok {
+		attribute, locator = "locator", url.PathEscape(v.(string))
+		description = fmt.Sprintf("build configuration matching %q", v.(string))
+	} else {
+		return diag.Errorf("error when retrieving build configuration, one of `build_config_id`, `name` or `locator` is required to be set")
+	}
+
+	var dt buildConfigSummary
+	if err := r.get(fmt.Sprintf("buildTypes/%s?fields=%s", locator, buildConfigSummaryFields), &dt); err != nil {
+		if isNotFoundError(err) {
+			return diag.Diagnostics{attributeError(attribute, fmt.Sprintf("%s was not found", description), err.Error())}
+		}
+		return diag.FromErr(describeAPIError(err, "Build Configuration"))
+	}
+
+	params, err := getParameters(r, buildConfigPath(dt.ID))
+	if err != nil {
+		return diag.FromErr(describeAPIError(err, "Build Configuration"))
+	}
+
+	d.SetId(dt.ID)
+	d.Set("build_config_id", dt.ID)
+	d.Set("name", dt.Name)
+	d.Set("project_id", dt.ProjectID)
+	d.Set("description", dt.Description)
+	d.Set("is_template", dt.TemplateFlag)
+	d.Set("url", dt.WebURL)
+	if err := flattenBuildConfigPause(d, &dt.buildConfigPause); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// the data source reads the values in effect, including the inherited ones, except for passwords
+	maps := make(map[string]map[string]string)
+	for attribute := range parameterPrefixes {
+		maps[attribute] = make(map[string]string)
+	}
+	for _, p := range params {
+		if p.Type != nil && strings.HasPrefix(p.Type.RawValue, "password") {
+			continue
+		}
+		attribute, name := parameterAttribute(p.Name)
+		maps[attribute][name] = p.Value
+	}
+	for attribute, values := range maps {
+		if err := d.Set(attribute, values); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	roots := make([]map[string]interface{}, 0)
+	if dt.VcsRootEntries != nil {
+		for _, e := range dt.VcsRootEntries.Items {
+			roots = append(roots, map[string]interface{}{
+				"id":             e.ID,
+				"checkout_rules": flattenCheckoutRules(e.CheckoutRules),
+			})
+		}
+	}
+	if err := d.Set("vcs_root", roots); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// locatorValue formats a value of a locator dimension. Values are wrapped in parentheses, so they may contain commas
+// and colons, unless they contain parentheses themselves: they're base64 encoded then, which TeamCity decodes using
+// the URL-safe alphabet.
+func locatorValue(v string) string {
+	if strings.ContainsAny(v, "()") {
+		return "$base64:" + base64.URLEncoding.EncodeToString([]byte(v))
+	}
+	return "(" + v + ")"
+}
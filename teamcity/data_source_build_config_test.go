@@ -0,0 +1,156 @@
+package teamcity_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceBuildConfig_Paused(t *testing.T) {
+	resName := "data.teamcity_build_config.build_config"
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceBuildConfigPaused,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "name", "Paused Build"),
+					resource.TestCheckResourceAttrPair(resName, "project_id", "teamcity_project.project", "id"),
+					resource.TestCheckResourceAttr(resName, "paused", "true"),
+					resource.TestCheckResourceAttr(resName, "paused_comment", "incident in progress"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceBuildConfig_Lookups(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceBuildConfigLookups,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.teamcity_build_config.by_id", "build_config_id", "teamcity_build_config.build_config", "id"),
+					resource.TestCheckResourceAttr("data.teamcity_build_config.by_id", "name", "Lookup Build"),
+					resource.TestCheckResourceAttr("data.teamcity_build_config.by_id", "description", "Looked up"),
+					resource.TestCheckResourceAttr("data.teamcity_build_config.by_id", "is_template", "false"),
+					resource.TestCheckResourceAttrSet("data.teamcity_build_config.by_id", "url"),
+					resource.TestCheckResourceAttr("data.teamcity_build_config.by_id", "env_params.DEPLOY_SERVER", "server.com"),
+					resource.TestCheckResourceAttr("data.teamcity_build_config.by_id", "config_params.github.repository", "nocode"),
+					resource.TestCheckResourceAttr("data.teamcity_build_config.by_id", "vcs_root.#", "1"),
+					resource.TestCheckResourceAttrPair("data.teamcity_build_config.by_id", "vcs_root.0.id", "teamcity_vcs_root_git.vcs", "id"),
+					resource.TestCheckResourceAttrPair("data.teamcity_build_config.by_name", "build_config_id", "teamcity_build_config.build_config", "id"),
+					resource.TestCheckResourceAttrPair("data.teamcity_build_config.by_locator", "build_config_id", "teamcity_build_config.build_config", "id"),
+					resource.TestCheckResourceAttrPair("data.teamcity_build_config.by_locator", "project_id", "teamcity_project.project", "id"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceBuildConfig_NameWithSpecialCharacters(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceBuildConfigSpecialName,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.teamcity_build_config.commas", "build_config_id", "teamcity_build_config.commas", "id"),
+					resource.TestCheckResourceAttrPair("data.teamcity_build_config.parentheses", "build_config_id", "teamcity_build_config.parentheses", "id"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceBuildConfigPaused = `
+resource "teamcity_project" "project" {
+	name = "Test Project"
+}
+
+resource "teamcity_build_config" "build_config" {
+	name = "Paused Build"
+	project_id = teamcity_project.project.id
+
+	paused = true
+	paused_comment = "incident in progress"
+}
+
+data "teamcity_build_config" "build_config" {
+  build_config_id = teamcity_build_config.build_config.id
+}
+`
+
+const testAccDataSourceBuildConfigLookups = `
+resource "teamcity_project" "project" {
+	name = "Test Project"
+}
+
+resource "teamcity_vcs_root_git" "vcs" {
+	name = "application"
+	project_id = teamcity_project.project.id
+	fetch_url = "https://github.com/kelseyhightower/nocode"
+	default_branch = "refs/head/master"
+}
+
+resource "teamcity_build_config" "build_config" {
+	name = "Lookup Build"
+	description = "Looked up"
+	project_id = teamcity_project.project.id
+
+	env_params = {
+		DEPLOY_SERVER = "server.com"
+	}
+
+	config_params = {
+		"github.repository" = "nocode"
+	}
+
+	vcs_root {
+		id = teamcity_vcs_root_git.vcs.id
+	}
+}
+
+data "teamcity_build_config" "by_id" {
+  build_config_id = teamcity_build_config.build_config.id
+}
+
+data "teamcity_build_config" "by_name" {
+  project_id = teamcity_project.project.id
+  name       = teamcity_build_config.build_config.name
+}
+
+data "teamcity_build_config" "by_locator" {
+  locator = "id:${teamcity_build_config.build_config.id}"
+}
+`
+
+const testAccDataSourceBuildConfigSpecialName = `
+resource "teamcity_project" "project" {
+	name = "Test Project"
+}
+
+resource "teamcity_build_config" "commas" {
+	name = "Build, test: deploy"
+	project_id = teamcity_project.project.id
+}
+
+resource "teamcity_build_config" "parentheses" {
+	name = "Build (nightly)"
+	project_id = teamcity_project.project.id
+}
+
+data "teamcity_build_config" "commas" {
+  project_id = teamcity_project.project.id
+  name       = teamcity_build_config.commas.name
+}
+
+data "teamcity_build_config" "parentheses" {
+  project_id = teamcity_project.project.id
+  name       = teamcity_build_config.parentheses.name
+}
+`
@@ -1,15 +1,17 @@
 package teamcity
 
 import (
+	"context"
 	"fmt"
 
 	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceProject() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceProjectRead,
+		ReadContext: dataSourceProjectRead,
 		Schema: map[string]*schema.Schema{
 			"project_id": {
 				Type:          schema.TypeString,
@@ -36,8 +38,8 @@ func dataSourceProject() *schema.Resource {
 	}
 }
 
-func dataSourceProjectRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func dataSourceProjectRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
 	var id, name string
 	var dt *api.Project
 
@@ -50,19 +52,25 @@ func dataSourceProjectRead(d *schema.ResourceData, meta interface{}) error {
 	if id != "" {
 		p, err := client.Projects.GetByID(id)
 		if err != nil {
-			return err
+			if isNotFoundError(err) {
+				return diag.Diagnostics{attributeError("project_id", fmt.Sprintf("project %q was not found", id), err.Error())}
+			}
+			return diag.FromErr(describeAPIError(err, "Project"))
 		}
 		dt = p
 	}
 	if name != "" {
 		p, err := client.Projects.GetByName(name)
 		if err != nil {
-			return err
+			if isNotFoundError(err) {
+				return diag.Diagnostics{attributeError("name", fmt.Sprintf("project named %q was not found", name), err.Error())}
+			}
+			return diag.FromErr(describeAPIError(err, "Project"))
 		}
 		dt = p
 	}
 	if dt == nil {
-		return fmt.Errorf("error when retrieving project, either `project_id` or `name` are required to be set")
+		return diag.Errorf("error when retrieving project, either `project_id` or `name` are required to be set")
 	}
 	d.SetId(dt.ID)
 	d.Set("name", dt.Name)
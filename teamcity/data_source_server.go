@@ -0,0 +1,63 @@
+package teamcity
+
+import (
+	"context"
+
+	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceServer() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceServerRead,
+		Schema: map[string]*schema.Schema{
+			"version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"version_major": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"version_minor": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"build_number": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"build_date": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"web_url": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceServerRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var server api.Server
+	if err := meta.(*Client).rest(ctx).get("server", &server); err != nil {
+		return diag.FromErr(describeAPIError(err, "Server"))
+	}
+
+	version, err := ParseServerVersion(server.Version)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(server.WebURL)
+	d.Set("version", server.Version)
+	d.Set("version_major", version.Major)
+	d.Set("version_minor", version.Minor)
+	d.Set("build_number", server.BuildNumber)
+	d.Set("build_date", server.BuildDate)
+	d.Set("web_url", server.WebURL)
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package teamcity_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceServer_Basic(t *testing.T) {
+	resName := "data.teamcity_server.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceServer,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr(resName, "version", regexp.MustCompile(`^\d{4}\.\d+`)),
+					resource.TestCheckResourceAttrSet(resName, "version_major"),
+					resource.TestCheckResourceAttrSet(resName, "version_minor"),
+					resource.TestCheckResourceAttrSet(resName, "build_number"),
+					resource.TestCheckResourceAttrSet(resName, "web_url"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceServer = `
+data "teamcity_server" "test" {
+}
+`
@@ -0,0 +1,44 @@
+package teamcity
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// attributeError returns an error diagnostic pointing at the given top-level attribute, so Terraform can show
+// which line of the configuration it relates to
+func attributeError(attribute, summary, detail string) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity:      diag.Error,
+		Summary:       summary,
+		Detail:        detail,
+		AttributePath: cty.GetAttrPath(attribute),
+	}
+}
+
+// attributeWarning returns a warning diagnostic pointing at the given top-level attribute
+func attributeWarning(attribute, summary, detail string) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity:      diag.Warning,
+		Summary:       summary,
+		Detail:        detail,
+		AttributePath: cty.GetAttrPath(attribute),
+	}
+}
+
+// validateBuildConfigExists checks the Build Configuration referenced by the given attribute exists, returning an
+// error diagnostic for the attribute when it doesn't
+func validateBuildConfigExists(ctx context.Context, client *Client, attribute, buildConfigID string) diag.Diagnostics {
+	if _, err := client.getBuildType(ctx, buildConfigID); err != nil {
+		if isNotFoundError(err) {
+			return diag.Diagnostics{attributeError(attribute,
+				fmt.Sprintf("invalid %s '%s'", attribute, buildConfigID),
+				"Build configuration does not exist")}
+		}
+		return diag.FromErr(describeAPIError(err, "Build Configuration"))
+	}
+	return nil
+}
@@ -1,7 +1,139 @@
 package teamcity
 
-import "strings"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// APIError is an error returned by the TeamCity REST API
+type APIError struct {
+	// StatusCode is the HTTP status of the response
+	StatusCode int
+	// Code is the TeamCity error type, e.g. "NotFoundException", when the response contains one
+	Code string
+	// Message is the error message returned by TeamCity
+	Message string
+
+	err error
+}
+
+func (e *APIError) Error() string {
+	if e.err != nil {
+		return e.err.Error()
+	}
+	if e.Message != "" {
+		return fmt.Sprintf("TeamCity API error %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("TeamCity API error %d", e.StatusCode)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+// go-teamcity doesn't expose response statuses, so errors it returns are recognised through the formats it produces.
+// Each expression is anchored to the start of the message so an ID or a body merely containing a status doesn't match.
+var apiErrorFormats = []*regexp.Regexp{
+	regexp.MustCompile(`(?s)^Error '(?P<status>\d{3})' when performing '\w+' operation - [^:]*: (?P<body>.*)$`),
+	regexp.MustCompile(`(?s)^Error '(?P<status>\d{3})' when deleting [^:]*: (?P<body>.*)$`),
+	regexp.MustCompile(`(?s)^(?P<status>\d{3}) Not Found - (?P<body>.*)$`),
+	regexp.MustCompile(`^Error when retrieving \w+ id = '.*', status: (?P<status>\d{3})$`),
+	regexp.MustCompile(`^Unknown error when [^,]*, statusCode: (?P<status>\d{3})$`),
+}
+
+// TeamCity error bodies look like:
+//
+//	Responding with error, status code: 404 (Not Found).
+//	Details: jetbrains.buildServer.server.rest.errors.NotFoundException: No project found by locator 'id:Foo'.
+var apiErrorDetails = regexp.MustCompile(`Details: (?:[\w$]+\.)*(?P<code>\w+): (?P<message>[^\n]*)`)
+
+// AsAPIError returns the TeamCity API error carried by err, if any
+func AsAPIError(err error) (*APIError, bool) {
+	if err == nil {
+		return nil, false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+
+	// the error may have been wrapped with some context, so each error of the chain is checked
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if out, ok := parseAPIError(e); ok {
+			return out, true
+		}
+	}
+
+	return nil, false
+}
+
+func parseAPIError(err error) (*APIError, bool) {
+	for _, format := range apiErrorFormats {
+		match := format.FindStringSubmatch(err.Error())
+		if match == nil {
+			continue
+		}
+
+		out := &APIError{err: err}
+		for i, name := range format.SubexpNames() {
+			switch name {
+			case "status":
+				out.StatusCode, _ = strconv.Atoi(match[i])
+			case "body":
+				out.Code, out.Message = parseAPIErrorBody(match[i])
+			}
+		}
+		return out, true
+	}
+
+	return nil, false
+}
+
+func parseAPIErrorBody(body string) (code string, message string) {
+	match := apiErrorDetails.FindStringSubmatch(body)
+	if match == nil {
+		return "", strings.TrimSpace(body)
+	}
+	return match[1], strings.TrimSpace(match[2])
+}
 
 func isNotFoundError(err error) bool {
-	return strings.Contains(err.Error(), "404")
+	apiErr, ok := AsAPIError(err)
+	return ok && (apiErr.StatusCode == http.StatusNotFound || apiErr.Code == "NotFoundException")
+}
+
+func isConflictError(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.StatusCode == http.StatusConflict
+}
+
+func isPermissionDeniedError(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden || apiErr.Code == "AccessDeniedException")
+}
+
+func isValidationError(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && (apiErr.StatusCode == http.StatusBadRequest || apiErr.Code == "BadRequestException" || apiErr.Code == "InvalidStateException")
+}
+
+// describeAPIError adds context to the errors which are usually caused by the environment rather than the resource itself
+func describeAPIError(err error, resource string) error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("timed out waiting for TeamCity to manage this %s, consider increasing the resource `timeouts`: %w", resource, err)
+	case isPermissionDeniedError(err):
+		return fmt.Errorf("the user configured for the provider isn't allowed to manage this %s: %w", resource, err)
+	case isConflictError(err):
+		return fmt.Errorf("this %s conflicts with its current state on the server, it may have been modified outside of Terraform: %w", resource, err)
+	case isValidationError(err):
+		return fmt.Errorf("the server rejected the settings of this %s: %w", resource, err)
+	}
+	return err
 }
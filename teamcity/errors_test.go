@@ -0,0 +1,83 @@
+package teamcity_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/cvbarros/terraform-provider-teamcity/teamcity"
+)
+
+func TestAsAPIError(t *testing.T) {
+	cases := []struct {
+		err        error
+		isAPIError bool
+		statusCode int
+		code       string
+		message    string
+	}{
+		{
+			err:        errors.New("Error '404' when performing 'GET' operation - projectFeature: Responding with error, status code: 404 (Not Found).\nDetails: jetbrains.buildServer.server.rest.errors.NotFoundException: No project found by locator 'id:Foo'.\nCould not find the entity requested."),
+			isAPIError: true,
+			statusCode: 404,
+			code:       "NotFoundException",
+			message:    "No project found by locator 'id:Foo'.",
+		},
+		{
+			err:        errors.New("Error '409' when deleting vcsRoot: Responding with error, status code: 409 (Conflict)."),
+			isAPIError: true,
+			statusCode: 409,
+			message:    "Responding with error, status code: 409 (Conflict).",
+		},
+		{
+			err:        errors.New("404 Not Found - Build feature (id: BUILD_EXT_1) for buildTypeId (id: Project_Build) was not found"),
+			isAPIError: true,
+			statusCode: 404,
+			message:    "Build feature (id: BUILD_EXT_1) for buildTypeId (id: Project_Build) was not found",
+		},
+		{
+			err:        errors.New("Error when retrieving BuildType id = 'Project_Build', status: 403"),
+			isAPIError: true,
+			statusCode: 403,
+		},
+		{
+			err:        errors.New("Unknown error when adding snapshot dependency, statusCode: 400"),
+			isAPIError: true,
+			statusCode: 400,
+		},
+		{
+			err:        fmt.Errorf("error reading project: %w", errors.New("Error '500' when performing 'GET' operation - project: boom")),
+			isAPIError: true,
+			statusCode: 500,
+			message:    "boom",
+		},
+		{
+			err:        errors.New("invalid build_config_id 'Build404' - Build configuration does not exist"),
+			isAPIError: false,
+		},
+		{
+			err:        errors.New("dial tcp 127.0.0.1:8111: connect: connection refused"),
+			isAPIError: false,
+		},
+	}
+
+	for _, c := range cases {
+		apiErr, ok := teamcity.AsAPIError(c.err)
+		if ok != c.isAPIError {
+			t.Errorf("AsAPIError(%q): expected %t but got %t", c.err, c.isAPIError, ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if apiErr.StatusCode != c.statusCode {
+			t.Errorf("AsAPIError(%q): expected status %d but got %d", c.err, c.statusCode, apiErr.StatusCode)
+		}
+		if apiErr.Code != c.code {
+			t.Errorf("AsAPIError(%q): expected code %q but got %q", c.err, c.code, apiErr.Code)
+		}
+		if apiErr.Message != c.message {
+			t.Errorf("AsAPIError(%q): expected message %q but got %q", c.err, c.message, apiErr.Message)
+		}
+	}
+}
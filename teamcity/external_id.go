@@ -0,0 +1,28 @@
+package teamcity
+
+import (
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// externalIDPattern matches the IDs TeamCity accepts for Projects and Build Configurations
+var externalIDPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{0,224}$`)
+
+// externalIDSchema is the schema of the `external_id` of Projects and Build Configurations, which TeamCity generates
+// from the name when it isn't set
+func externalIDSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Computed: true,
+		ValidateFunc: validation.StringMatch(externalIDPattern,
+			"must start with a Latin letter, contain only Latin letters, digits and underscores, and be at most 225 characters long"),
+	}
+}
+
+// changeExternalID changes the ID of the Project or Build Configuration at path, which keeps its history and settings
+func changeExternalID(r *restClient, path string, id string) error {
+	return r.putText(path+"/id", id)
+}
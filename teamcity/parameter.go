@@ -0,0 +1,437 @@
+package teamcity
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// parameter is a parameter of a Project or Build Configuration as represented by the TeamCity REST API, including its
+// specification which go-teamcity doesn't support
+type parameter struct {
+	Name      string         `json:"name"`
+	Value     string         `json:"value"`
+	Inherited *bool          `json:"inherited,omitempty"`
+	Type      *parameterType `json:"type,omitempty"`
+}
+
+type parameterType struct {
+	RawValue string `json:"rawValue"`
+}
+
+type parameters struct {
+	Count int          `json:"count"`
+	Items []*parameter `json:"property"`
+}
+
+// parameterPrefixes maps the attributes holding plain parameters to the prefix of their names
+var parameterPrefixes = map[string]string{
+	"config_params": "",
+	"env_params":    "env.",
+	"sys_params":    "system.",
+}
+
+func getParameters(r *restClient, path string) ([]*parameter, error) {
+	var out parameters
+	if err := r.get(path+"/parameters", &out); err != nil {
+		return nil, err
+	}
+	return out.Items, nil
+}
+
+// setParameters replaces all parameters defined by a Project or Build Configuration
+func setParameters(r *restClient, path string, params []*parameter) error {
+	return r.put(path+"/parameters", &parameters{Count: len(params), Items: params}, nil)
+}
+
+func projectPath(id string) string {
+	return fmt.Sprintf("projects/%s", api.LocatorID(id))
+}
+
+func buildConfigPath(id string) string {
+	return fmt.Sprintf("buildTypes/%s", api.LocatorID(id))
+}
+
+// parameterSpecSchema is the schema of the `param` blocks of Projects and Build Configurations
+func parameterSpecSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"value": {
+					Type:      schema.TypeString,
+					Optional:  true,
+					Sensitive: true,
+				},
+				"type": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "text",
+					ValidateFunc: validation.StringInSlice([]string{"text", "password", "checkbox", "select"}, false),
+				},
+				"label": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"description": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"display": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "normal",
+					ValidateFunc: validation.StringInSlice([]string{"normal", "hidden", "prompt"}, false),
+				},
+				"read_only": {
+					Type:     schema.TypeBool,
+					Optional: true,
+				},
+				"validation_regex": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"validation_message": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"options": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+				"allow_multiple": {
+					Type:     schema.TypeBool,
+					Optional: true,
+				},
+				"checked_value": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"unchecked_value": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+			},
+		},
+	}
+}
+
+// validateParameterSpecsDiff checks the `param` blocks only set the attributes supported by their type, so mistakes
+// are reported at plan time
+func validateParameterSpecsDiff(_ context.Context, diff *schema.ResourceDiff, _ interface{}) error {
+	for _, raw := range diff.Get("param").(*schema.Set).List() {
+		dt := raw.(map[string]interface{})
+		// the name isn't known yet when interpolated from other resources
+		if dt["name"].(string) == "" {
+			continue
+		}
+		if _, err := expandParameterSpec(dt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandParameterSpecs returns the plain parameters of the `*_params` maps, and the ones of the `param` blocks with
+// their specification
+func expandParameterSpecs(d *schema.ResourceData) ([]*parameter, error) {
+	byName := make(map[string]*parameter)
+	for attribute, prefix := range parameterPrefixes {
+		for k, v := range d.Get(attribute).(map[string]interface{}) {
+			byName[prefix+k] = &parameter{Name: prefix + k, Value: v.(string)}
+		}
+	}
+
+	for _, raw := range d.Get("param").(*schema.Set).List() {
+		p, err := expandParameterSpec(raw.(map[string]interface{}))
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := byName[p.Name]; ok {
+			return nil, fmt.Errorf("parameter '%s' is defined more than once, either by a `param` block or the `*_params` attributes", p.Name)
+		}
+		byName[p.Name] = p
+	}
+
+	out := make([]*parameter, 0, len(byName))
+	for _, p := range byName {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+func expandParameterSpec(dt map[string]interface{}) (*parameter, error) {
+	name := dt["name"].(string)
+	specType := dt["type"].(string)
+	attributes := make(map[string]string)
+
+	if v := dt["label"].(string); v != "" {
+		attributes["label"] = v
+	}
+	if v := dt["description"].(string); v != "" {
+		attributes["description"] = v
+	}
+	if v := dt["display"].(string); v != "normal" {
+		attributes["display"] = v
+	}
+	if dt["read_only"].(bool) {
+		attributes["readOnly"] = "true"
+	}
+
+	unsupported := func(attribute string) error {
+		return fmt.Errorf("parameter '%s': `%s` isn't supported by %s parameters", name, attribute, specType)
+	}
+	options := dt["options"].([]interface{})
+	if specType != "select" {
+		if len(options) > 0 {
+			return nil, unsupported("options")
+		}
+		if dt["allow_multiple"].(bool) {
+			return nil, unsupported("allow_multiple")
+		}
+	}
+	if specType != "checkbox" && (dt["checked_value"].(string) != "" || dt["unchecked_value"].(string) != "") {
+		return nil, unsupported("checked_value")
+	}
+	if specType != "text" && (dt["validation_regex"].(string) != "" || dt["validation_message"].(string) != "") {
+		return nil, unsupported("validation_regex")
+	}
+
+	switch specType {
+	case "text":
+		if v := dt["validation_regex"].(string); v != "" {
+			attributes["validationMode"] = "regex"
+			attributes["regexp"] = v
+		}
+		if v := dt["validation_message"].(string); v != "" {
+			attributes["validationMessage"] = v
+		}
+	case "select":
+		if len(options) == 0 {
+			return nil, fmt.Errorf("parameter '%s': `options` is required for select parameters", name)
+		}
+		for i, o := range options {
+			attributes[fmt.Sprintf("data_%d", i+1)] = o.(string)
+		}
+		if dt["allow_multiple"].(bool) {
+			attributes["multiple"] = "true"
+		}
+	case "checkbox":
+		if v := dt["checked_value"].(string); v != "" {
+			attributes["checkedValue"] = v
+		}
+		if v := dt["unchecked_value"].(string); v != "" {
+			attributes["uncheckedValue"] = v
+		}
+	}
+
+	p := &parameter{Name: name, Value: dt["value"].(string)}
+	// a text parameter without any specification is a plain parameter
+	if specType != "text" || len(attributes) > 0 {
+		p.Type = &parameterType{RawValue: formatParameterSpec(specType, attributes)}
+	}
+	return p, nil
+}
+
+// flattenParameterSpecs sets the parameters defined by a Project or Build Configuration. Parameters with a
+// specification, or previously managed by a `param` block, are set in the `param` blocks, and the others in the
+// `*_params` maps. The value of password parameters is never returned by TeamCity, so the one of the state is kept.
+// Parameters inherited from templates or parent projects aren't managed, and are only set in `inherited_params`.
+func flattenParameterSpecs(d *schema.ResourceData, params []*parameter) error {
+	managed := make(map[string]map[string]interface{})
+	for _, raw := range d.Get("param").(*schema.Set).List() {
+		dt := raw.(map[string]interface{})
+		managed[dt["name"].(string)] = dt
+	}
+
+	maps := make(map[string]map[string]string)
+	for attribute := range parameterPrefixes {
+		maps[attribute] = make(map[string]string)
+	}
+	specs := make([]interface{}, 0)
+	inherited := make(map[string]string)
+	for _, p := range params {
+		if p.Inherited != nil && *p.Inherited {
+			if p.Type == nil || !strings.HasPrefix(p.Type.RawValue, "password") {
+				inherited[p.Name] = p.Value
+			}
+			continue
+		}
+
+		prior, isManaged := managed[p.Name]
+		if p.Type == nil && !isManaged {
+			attribute, name := parameterAttribute(p.Name)
+			maps[attribute][name] = p.Value
+			continue
+		}
+
+		m, err := flattenParameterSpec(p)
+		if err != nil {
+			return err
+		}
+		if m["type"] == "password" && isManaged {
+			m["value"] = prior["value"]
+		}
+		specs = append(specs, m)
+	}
+
+	for attribute, values := range maps {
+		if err := d.Set(attribute, values); err != nil {
+			return err
+		}
+	}
+	if err := d.Set("inherited_params", inherited); err != nil {
+		return err
+	}
+	return d.Set("param", specs)
+}
+
+func flattenParameterSpec(p *parameter) (map[string]interface{}, error) {
+	m := map[string]interface{}{
+		"name":               p.Name,
+		"value":              p.Value,
+		"type":               "text",
+		"label":              "",
+		"description":        "",
+		"display":            "normal",
+		"read_only":          false,
+		"validation_regex":   "",
+		"validation_message": "",
+		"options":            []interface{}{},
+		"allow_multiple":     false,
+		"checked_value":      "",
+		"unchecked_value":    "",
+	}
+	if p.Type == nil || p.Type.RawValue == "" {
+		return m, nil
+	}
+
+	specType, attributes, err := parseParameterSpec(p.Type.RawValue)
+	if err != nil {
+		return nil, fmt.Errorf("parameter '%s': %w", p.Name, err)
+	}
+	m["type"] = specType
+	m["label"] = attributes["label"]
+	m["description"] = attributes["description"]
+	if v, ok := attributes["display"]; ok {
+		m["display"] = v
+	}
+	m["read_only"] = attributes["readOnly"] == "true"
+	m["validation_regex"] = attributes["regexp"]
+	m["validation_message"] = attributes["validationMessage"]
+	m["allow_multiple"] = attributes["multiple"] == "true"
+	m["checked_value"] = attributes["checkedValue"]
+	m["unchecked_value"] = attributes["uncheckedValue"]
+
+	options := make([]interface{}, 0)
+	for i := 1; ; i++ {
+		v, ok := attributes[fmt.Sprintf("data_%d", i)]
+		if !ok {
+			break
+		}
+		options = append(options, v)
+	}
+	m["options"] = options
+	return m, nil
+}
+
+// parameterAttribute returns the `*_params` attribute holding a plain parameter, and its name without prefix
+func parameterAttribute(name string) (string, string) {
+	for attribute, prefix := range parameterPrefixes {
+		if prefix != "" && strings.HasPrefix(name, prefix) {
+			return attribute, strings.TrimPrefix(name, prefix)
+		}
+	}
+	return "config_params", name
+}
+
+// formatParameterSpec formats the specification of a parameter as TeamCity does, for example:
+//
+//	select data_1='staging' data_2='production' display='prompt' label='Environment'
+func formatParameterSpec(specType string, attributes map[string]string) string {
+	keys := make([]string, 0, len(attributes))
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(specType)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s='%s'", k, escapeParameterSpecValue(attributes[k]))
+	}
+	return b.String()
+}
+
+func parseParameterSpec(raw string) (string, map[string]string, error) {
+	raw = strings.TrimSpace(raw)
+	specType := raw
+	rest := ""
+	if i := strings.IndexAny(raw, " \t"); i >= 0 {
+		specType, rest = raw[:i], raw[i:]
+	}
+
+	attributes := make(map[string]string)
+	for {
+		rest = strings.TrimLeft(rest, " \t")
+		if rest == "" {
+			return specType, attributes, nil
+		}
+		i := strings.Index(rest, "='")
+		if i <= 0 {
+			return "", nil, fmt.Errorf("invalid specification %s", strconv.Quote(raw))
+		}
+		key := rest[:i]
+
+		var value strings.Builder
+		closed := false
+		j := i + 2
+		for ; j < len(rest); j++ {
+			c := rest[j]
+			if c == '|' && j+1 < len(rest) {
+				j++
+				value.WriteByte(unescapeParameterSpecChar(rest[j]))
+				continue
+			}
+			if c == '\'' {
+				closed = true
+				break
+			}
+			value.WriteByte(c)
+		}
+		if !closed {
+			return "", nil, fmt.Errorf("invalid specification %s", strconv.Quote(raw))
+		}
+		attributes[key] = value.String()
+		rest = rest[j+1:]
+	}
+}
+
+var parameterSpecEscaper = strings.NewReplacer("|", "||", "'", "|'", "\n", "|n", "\r", "|r", "[", "|[", "]", "|]")
+
+func escapeParameterSpecValue(v string) string {
+	return parameterSpecEscaper.Replace(v)
+}
+
+func unescapeParameterSpecChar(c byte) byte {
+	switch c {
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	}
+	return c
+}
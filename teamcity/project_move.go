@@ -0,0 +1,68 @@
+package teamcity
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	api "github.com/cvbarros/go-teamcity/teamcity"
+)
+
+// moveBuildConfig moves a Build Configuration to another Project, which keeps its ID and build history
+func moveBuildConfig(r *restClient, id string, projectID string) error {
+	return r.put(buildConfigPath(id)+"/project", &api.ProjectReference{ID: projectID}, nil)
+}
+
+// moveVcsRoot moves a VCS Root to another Project, which keeps its ID and the Build Configurations attached to it
+func moveVcsRoot(r *restClient, id string, projectID string) error {
+	users, err := vcsRootUsersOutside(r, id, projectID)
+	if err != nil {
+		return err
+	}
+	// TeamCity only allows attaching a VCS Root to the Build Configurations of its Project and subprojects
+	if len(users) > 0 {
+		return fmt.Errorf("VCS Root '%s' can't be moved to project '%s', as it's attached to Build Configurations outside of it: %s. Move them, or detach the VCS Root from them, first", id, projectID, strings.Join(users, ", "))
+	}
+	return r.putText(fmt.Sprintf("vcs-roots/%s/projectId", api.LocatorID(id)), projectID)
+}
+
+type buildConfigReferences struct {
+	Items []*struct {
+		ID string `json:"id"`
+	} `json:"buildType"`
+}
+
+// vcsRootUsersOutside returns the IDs of the Build Configurations and templates using a VCS Root, which aren't in a
+// Project or its subprojects
+func vcsRootUsersOutside(r *restClient, id string, projectID string) ([]string, error) {
+	find := func(locator string) (map[string]bool, error) {
+		var out buildConfigReferences
+		if err := r.get(fmt.Sprintf("buildTypes?locator=%s&fields=buildType(id)", url.QueryEscape(locator)), &out); err != nil {
+			return nil, err
+		}
+		ids := make(map[string]bool, len(out.Items))
+		for _, i := range out.Items {
+			ids[i.ID] = true
+		}
+		return ids, nil
+	}
+
+	all, err := find(fmt.Sprintf("vcsRoot:(id:%s),templateFlag:any", id))
+	if err != nil {
+		return nil, err
+	}
+	inside, err := find(fmt.Sprintf("vcsRoot:(id:%s),templateFlag:any,affectedProject:(id:%s)", id, projectID))
+	if err != nil {
+		return nil, err
+	}
+
+	var out []string
+	for id := range all {
+		if !inside[id] {
+			out = append(out, id)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
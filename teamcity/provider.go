@@ -1,9 +1,15 @@
 package teamcity
 
 import (
+	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/cvbarros/terraform-provider-teamcity/internal/transport"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 // Provider is the plugin entry point
@@ -25,17 +31,33 @@ func Provider() *schema.Provider {
 			"teamcity_agent_pool":                         resourceAgentPool(),
 			"teamcity_agent_pool_project_assignment":      resourceAgentPoolProjectAssignment(),
 			"teamcity_feature_golang":                     resourceFeatureGolang(),
+			"teamcity_build_failure_condition_text":       resourceBuildFailureConditionText(),
+			"teamcity_build_failure_condition_metric":     resourceBuildFailureConditionMetric(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"teamcity_agent_pool": dataSourceAgentPool(),
-			"teamcity_project":    dataSourceProject(),
+			"teamcity_agent_pool":   dataSourceAgentPool(),
+			"teamcity_build_config": dataSourceBuildConfig(),
+			"teamcity_project":      dataSourceProject(),
+			"teamcity_server":       dataSourceServer(),
 		},
 		Schema: map[string]*schema.Schema{
 			"address": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("TEAMCITY_ADDR", nil),
 			},
+			"profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("TEAMCITY_PROFILE", nil),
+				Description: "Name of the profile in the credentials file to read the address and credentials from.",
+			},
+			"credentials_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("TEAMCITY_CREDENTIALS_FILE", DefaultCredentialsFile),
+				Description: "Path to the credentials file containing the profiles.",
+			},
 			"token": {
 				Type:          schema.TypeString,
 				Optional:      true,
@@ -54,17 +76,75 @@ func Provider() *schema.Provider {
 				ConflictsWith: []string{"token"},
 				DefaultFunc:   schema.EnvDefaultFunc("TEAMCITY_PASSWORD", nil),
 			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      transport.DefaultMaxRetries,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of times a request failing with a transient error (e.g. 502/503) is retried.",
+			},
+			"max_retry_wait": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      int(transport.DefaultMaxRetryWait / time.Second),
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Maximum time to wait between two retries, in seconds.",
+			},
+			"ca_cert_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"ca_cert_pem"},
+				DefaultFunc:   schema.EnvDefaultFunc("TEAMCITY_CA_CERT_FILE", nil),
+				Description:   "Path to a PEM-encoded CA bundle used to verify the TeamCity server certificate.",
+			},
+			"ca_cert_pem": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"ca_cert_file"},
+				DefaultFunc:   schema.EnvDefaultFunc("TEAMCITY_CA_CERT_PEM", nil),
+				Description:   "PEM-encoded CA bundle used to verify the TeamCity server certificate.",
+			},
+			"client_cert": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"client_key"},
+				DefaultFunc:  schema.EnvDefaultFunc("TEAMCITY_CLIENT_CERT", nil),
+				Description:  "PEM-encoded client certificate, or a path to it, used to authenticate with mutual TLS.",
+			},
+			"client_key": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				RequiredWith: []string{"client_cert"},
+				DefaultFunc:  schema.EnvDefaultFunc("TEAMCITY_CLIENT_KEY", nil),
+				Description:  "PEM-encoded private key, or a path to it, matching `client_cert`.",
+			},
+			"insecure_skip_verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("TEAMCITY_INSECURE_SKIP_VERIFY", false),
+				Description: "Disables the verification of the TeamCity server certificate. Not recommended outside of testing.",
+			},
 		},
 
-		ConfigureFunc: providerConfigure,
+		ConfigureContextFunc: providerConfigure,
 	}
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	config := Config{
 		Address:  d.Get("address").(string),
 		Username: d.Get("username").(string),
 		Password: d.Get("password").(string),
+
+		MaxRetries:   d.Get("max_retries").(int),
+		MaxRetryWait: time.Duration(d.Get("max_retry_wait").(int)) * time.Second,
+
+		CACertFile:         d.Get("ca_cert_file").(string),
+		CACertPEM:          d.Get("ca_cert_pem").(string),
+		ClientCert:         d.Get("client_cert").(string),
+		ClientKey:          d.Get("client_key").(string),
+		InsecureSkipVerify: d.Get("insecure_skip_verify").(bool),
 	}
 
 	if v, ok := d.GetOk("token"); ok && v.(string) != "" {
@@ -74,9 +154,56 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		config.Password = d.Get("password").(string)
 	}
 
+	// the address of the profile is always used with its credentials, so they're never sent to another server. The
+	// credentials set in the provider configuration, or environment variables, take precedence over the ones of the
+	// profile.
+	if v, ok := d.GetOk("profile"); ok {
+		profile, err := LoadCredentialsProfile(d.Get("credentials_file").(string), v.(string))
+		if err != nil {
+			return nil, diag.Diagnostics{attributeError("profile", "Error configuring provider: unable to load profile", err.Error())}
+		}
+		if profile.Address == "" {
+			return nil, diag.Diagnostics{attributeError("profile",
+				"Error configuring provider: the profile doesn't specify an `address`",
+				fmt.Sprintf("Set the `address` of the TeamCity server profile %q is for.", v.(string)))}
+		}
+		if config.Address != "" && strings.TrimSuffix(config.Address, "/") != strings.TrimSuffix(profile.Address, "/") {
+			return nil, diag.Diagnostics{attributeError("address",
+				"Error configuring provider: `address` differs from the one of the selected `profile`",
+				fmt.Sprintf("The address %q doesn't match the address %q of profile %q. Remove `address`, or the TEAMCITY_ADDR environment variable, to use the profile.", config.Address, profile.Address, v.(string)))}
+		}
+		config.Address = profile.Address
+		if config.Token == "" && config.Username == "" {
+			config.Token = profile.Token
+			config.Username = profile.Username
+			config.Password = profile.Password
+		}
+	}
+
+	if config.Address == "" {
+		return nil, diag.Diagnostics{attributeError("address",
+			"Error configuring provider: `address` must be specified",
+			"Set `address` in the provider configuration, through the TEAMCITY_ADDR environment variable, or in the selected `profile`.")}
+	}
+
 	if config.Token == "" && config.Username == "" {
-		return nil, fmt.Errorf("Error configuring provider: either a `token` or `username` must be specified")
+		return nil, diag.Diagnostics{attributeError("token",
+			"Error configuring provider: either a `token` or `username` must be specified",
+			"Set `token`, or `username` and `password`, in the provider configuration, through the TEAMCITY_TOKEN, TEAMCITY_USER and TEAMCITY_PASSWORD environment variables, or in the selected `profile`.")}
 	}
 
-	return config.Client()
+	client, err := config.Client(ctx)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	var diags diag.Diagnostics
+	if err := client.detectServerVersion(ctx); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Unable to detect the TeamCity server version",
+			Detail:   fmt.Sprintf("Features which aren't supported by the server won't be detected until they're applied: %s", err),
+		})
+	}
+	return client, diags
 }
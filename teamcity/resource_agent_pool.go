@@ -1,12 +1,13 @@
 package teamcity
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strconv"
-	"strings"
 
 	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -14,9 +15,9 @@ func resourceAgentPool() *schema.Resource {
 	return &schema.Resource{
 		// the TC Docs say this supports update - but the documented API doesn't work
 		// (returns 405 Method Not Allowed) so for the moment this can't support update
-		Create: resourceAgentPoolCreate,
-		Read:   resourceAgentPoolRead,
-		Delete: resourceAgentPoolDelete,
+		CreateContext: resourceAgentPoolCreate,
+		ReadContext:   resourceAgentPoolRead,
+		DeleteContext: resourceAgentPoolDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -37,8 +38,8 @@ func resourceAgentPool() *schema.Resource {
 	}
 }
 
-func resourceAgentPoolCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceAgentPoolCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
 
 	agentPool := api.CreateAgentPool{
 		Name: d.Get("name").(string),
@@ -49,31 +50,31 @@ func resourceAgentPoolCreate(d *schema.ResourceData, meta interface{}) error {
 
 	createdAgentPool, err := client.AgentPools.Create(agentPool)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(fmt.Sprintf("%d", createdAgentPool.Id))
 
-	return resourceAgentPoolRead(d, client)
+	return resourceAgentPoolRead(ctx, d, meta)
 }
 
-func resourceAgentPoolRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceAgentPoolRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
 
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	agentPool, err := client.AgentPools.GetByID(id)
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
+		if isNotFoundError(err) {
 			log.Printf("[DEBUG] Agent Pool not found - removing from state!")
 			d.SetId("")
 			return nil
 		}
 
-		return err
+		return diag.FromErr(describeAPIError(err, "Agent Pool"))
 	}
 
 	d.Set("name", agentPool.Name)
@@ -86,13 +87,19 @@ func resourceAgentPoolRead(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func resourceAgentPoolDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceAgentPoolDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
 
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	return client.AgentPools.Delete(id)
+	if err := client.AgentPools.Delete(id); err != nil {
+		if !isNotFoundError(err) {
+			return diag.FromErr(describeAPIError(err, "Agent Pool"))
+		}
+	}
+
+	return nil
 }
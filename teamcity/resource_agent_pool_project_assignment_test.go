@@ -1,12 +1,12 @@
 package teamcity_test
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
 	"time"
 
-	api "github.com/cvbarros/go-teamcity/teamcity"
 	"github.com/cvbarros/terraform-provider-teamcity/teamcity"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -77,7 +77,7 @@ func TestAccTeamCityAgentPoolProjectAssignment_DisassocateOthers(t *testing.T) {
 
 func testAccCheckTeamCityAgentPoolProjectAssignmentExists(resourceName string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 		rs, ok := s.RootModule().Resources[resourceName]
 		if !ok {
 			return fmt.Errorf("Not found: %s", resourceName)
@@ -111,7 +111,7 @@ func testAccCheckTeamCityAgentPoolProjectAssignmentExists(resourceName string) r
 
 func testAccCheckTeamCityAgentPoolProjectAssignmentOnlyContains(resourceName string, agentPoolName string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 		rs, ok := s.RootModule().Resources[resourceName]
 		if !ok {
 			return fmt.Errorf("Not found: %s", resourceName)
@@ -135,7 +135,7 @@ func testAccCheckTeamCityAgentPoolProjectAssignmentOnlyContains(resourceName str
 }
 
 func testAccCheckTeamCityAgentPoolProjectAssignmentDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*api.Client)
+	client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "teamcity_agent_pool_project_assignment" {
 			continue
@@ -1,13 +1,14 @@
 package teamcity_test
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
 	"testing"
 	"time"
 
-	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/cvbarros/terraform-provider-teamcity/teamcity"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -66,7 +67,7 @@ func TestAccTeamCityAgentPool_Complete(t *testing.T) {
 
 func testAccCheckTeamCityAgentPoolExists(n string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 		rs, ok := s.RootModule().Resources[n]
 		if !ok {
 			return fmt.Errorf("Not found: %s", n)
@@ -87,7 +88,7 @@ func testAccCheckTeamCityAgentPoolExists(n string) resource.TestCheckFunc {
 }
 
 func testAccCheckTeamCityAgentPoolDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*api.Client)
+	client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 	for _, r := range s.RootModule().Resources {
 		if r.Type != "teamcity_agent_pool" {
 			continue
@@ -1,20 +1,21 @@
 package teamcity
 
 import (
+	"context"
 	"fmt"
 	"log"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
-
 	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceAgentRequirement() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceAgentRequirementCreate,
-		Read:   resourceAgentRequirementRead,
-		Delete: resourceAgentRequirementDelete,
+		CreateContext: resourceAgentRequirementCreate,
+		ReadContext:   resourceAgentRequirementRead,
+		DeleteContext: resourceAgentRequirementDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -45,20 +46,23 @@ func resourceAgentRequirement() *schema.Resource {
 	}
 }
 
-func resourceAgentRequirementCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceAgentRequirementCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	r := meta.(*Client).rest(ctx)
+	unlock, err := meta.(*Client).lockBuildType(ctx, d.Get("build_config_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
 	var buildConfigID string
 
 	if v, ok := d.GetOk("build_config_id"); ok {
 		buildConfigID = v.(string)
 	}
 	// validates the Build Configuration exists
-	if _, err := client.BuildTypes.GetByID(buildConfigID); err != nil {
-		return fmt.Errorf("invalid build_config_id '%s' - Build configuration does not exist", buildConfigID)
+	if diags := validateBuildConfigExists(ctx, meta.(*Client), "build_config_id", buildConfigID); diags.HasError() {
+		return diags
 	}
 
-	srv := client.AgentRequirementService(buildConfigID)
-
 	var condition, name, value string
 
 	if v, ok := d.GetOk("condition"); ok {
@@ -75,23 +79,20 @@ func resourceAgentRequirementCreate(d *schema.ResourceData, meta interface{}) er
 
 	dt, err := api.NewAgentRequirement(condition, name, value)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	out, err := srv.Create(dt)
-
-	if err != nil {
-		return err
+	var out api.AgentRequirement
+	if err := r.post(agentRequirementsPath(buildConfigID), dt, &out); err != nil {
+		return diag.FromErr(describeAPIError(err, "Agent Requirement"))
 	}
 
 	d.SetId(out.ID)
 
-	return resourceAgentRequirementRead(d, meta)
+	return resourceAgentRequirementRead(ctx, d, meta)
 }
 
-func resourceAgentRequirementRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client).AgentRequirementService(d.Get("build_config_id").(string))
-
-	dt, err := getAgentRequirement(client, d.Id())
+func resourceAgentRequirementRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	dt, err := getAgentRequirement(meta.(*Client).rest(ctx), d.Get("build_config_id").(string), d.Id())
 	if err != nil {
 		// handles this being deleted outside of TF
 		if isNotFoundError(err) {
@@ -100,42 +101,55 @@ func resourceAgentRequirementRead(d *schema.ResourceData, meta interface{}) erro
 			return nil
 		}
 
-		return err
+		return diag.FromErr(describeAPIError(err, "Agent Requirement"))
 	}
 
 	if err := d.Set("build_config_id", dt.BuildTypeID); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if err := d.Set("condition", dt.Condition); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if err := d.Set("name", dt.Name()); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if v := dt.Value(); v != "" {
 		if err := d.Set("value", v); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	return nil
 }
 
-func resourceAgentRequirementDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
-	svr := client.AgentRequirementService(d.Get("build_config_id").(string))
+func resourceAgentRequirementDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	r := meta.(*Client).rest(ctx)
+	unlock, err := meta.(*Client).lockBuildType(ctx, d.Get("build_config_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
+	if err := r.delete(fmt.Sprintf("%s/%s", agentRequirementsPath(d.Get("build_config_id").(string)), d.Id())); err != nil {
+		if !isNotFoundError(err) {
+			return diag.FromErr(describeAPIError(err, "Agent Requirement"))
+		}
+	}
 
-	return svr.Delete(d.Id())
+	return nil
 }
 
-func getAgentRequirement(c *api.AgentRequirementService, id string) (*api.AgentRequirement, error) {
-	dt, err := c.GetByID(id)
-	if err != nil {
+func agentRequirementsPath(buildConfigID string) string {
+	return buildConfigPath(buildConfigID) + "/agent-requirements"
+}
+
+func getAgentRequirement(r *restClient, buildConfigID string, id string) (*api.AgentRequirement, error) {
+	var out api.AgentRequirement
+	if err := r.get(fmt.Sprintf("%s/%s", agentRequirementsPath(buildConfigID), id), &out); err != nil {
 		return nil, err
 	}
-
-	return dt, nil
+	out.BuildTypeID = buildConfigID
+	return &out, nil
 }
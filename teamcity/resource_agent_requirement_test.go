@@ -1,11 +1,13 @@
 package teamcity_test
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
 
 	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/cvbarros/terraform-provider-teamcity/teamcity"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -73,7 +75,7 @@ func TestAccTeamcityAgentRequirement_Update(t *testing.T) {
 
 func testAccCheckTeamcityAgentRequirementDestroy(bt *string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 		return AgentRequirementDestroyHelper(s, bt, client)
 	}
 }
@@ -101,7 +103,7 @@ func AgentRequirementDestroyHelper(s *terraform.State, bt *string, client *api.C
 
 func testAccCheckTeamcityAgentRequirementExists(n string, bt *string, snap *api.AgentRequirement) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 		return teamcityAgentRequirementExistsHelper(n, bt, s, client, snap)
 	}
 }
@@ -1,19 +1,21 @@
 package teamcity
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceArtifactDependency() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceArtifactDependencyCreate,
-		Read:   resourceArtifactDependencyRead,
-		Delete: resourceArtifactDependencyDelete,
+		CreateContext: resourceArtifactDependencyCreate,
+		ReadContext:   resourceArtifactDependencyRead,
+		DeleteContext: resourceArtifactDependencyDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -64,43 +66,44 @@ func resourceArtifactDependency() *schema.Resource {
 	}
 }
 
-func resourceArtifactDependencyCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceArtifactDependencyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	r := meta.(*Client).rest(ctx)
+	unlock, err := meta.(*Client).lockBuildType(ctx, d.Get("build_config_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
 	var buildConfigID string
 
 	if v, ok := d.GetOk("build_config_id"); ok {
 		buildConfigID = v.(string)
 	}
 	// validates the Build Configuration exists
-	if _, err := client.BuildTypes.GetByID(buildConfigID); err != nil {
-		return fmt.Errorf("invalid build_config_id '%s' - Build configuration does not exist", buildConfigID)
+	if diags := validateBuildConfigExists(ctx, meta.(*Client), "build_config_id", buildConfigID); diags.HasError() {
+		return diags
 	}
 
-	depService := client.DependencyService(buildConfigID)
 	opt, err := expandArtifactDependencyOptions(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	dep, err := api.NewArtifactDependency(d.Get("source_build_config_id").(string), opt)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	out, err := depService.AddArtifactDependency(dep)
-
-	if err != nil {
-		return err
+	var out api.ArtifactDependency
+	if err := r.post(artifactDependenciesPath(buildConfigID), dep, &out); err != nil {
+		return diag.FromErr(describeAPIError(err, "Artifact Dependency"))
 	}
 
 	d.SetId(out.ID())
 
-	return resourceArtifactDependencyRead(d, meta)
+	return resourceArtifactDependencyRead(ctx, d, meta)
 }
 
-func resourceArtifactDependencyRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client).DependencyService(d.Get("build_config_id").(string))
-
-	dt, err := getArtifactDependency(client, d.Id())
+func resourceArtifactDependencyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	dt, err := getArtifactDependency(meta.(*Client).rest(ctx), d.Get("build_config_id").(string), d.Id())
 	if err != nil {
 		// handles this being deleted outside of TF
 		if isNotFoundError(err) {
@@ -109,49 +112,61 @@ func resourceArtifactDependencyRead(d *schema.ResourceData, meta interface{}) er
 			return nil
 		}
 
-		return err
+		return diag.FromErr(describeAPIError(err, "Artifact Dependency"))
 	}
 
 	if err := d.Set("build_config_id", dt.BuildTypeID()); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if dt.Options.CleanDestination {
 		if err := d.Set("clean_destination", dt.Options.CleanDestination); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 	if err := d.Set("dependency_revision", string(dt.Options.ArtifactRevisionType)); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("path_rules", flattenStringSlice(dt.Options.PathRules)); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if dt.Options.ArtifactRevisionType == api.BuildWithSpecifiedNumber || dt.Options.ArtifactRevisionType == api.LastBuildFinishedWithTag {
 		if err := d.Set("revision", dt.Options.RevisionNumber); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	} else {
 		d.Set("revision", nil)
 	}
 
-	return d.Set("source_build_config_id", dt.SourceBuildTypeID)
+	return diag.FromErr(d.Set("source_build_config_id", dt.SourceBuildTypeID))
 }
 
-func resourceArtifactDependencyDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
-	dep := client.DependencyService(d.Get("build_config_id").(string))
+func resourceArtifactDependencyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	r := meta.(*Client).rest(ctx)
+	unlock, err := meta.(*Client).lockBuildType(ctx, d.Get("build_config_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
+	if err := r.delete(fmt.Sprintf("%s/%s", artifactDependenciesPath(d.Get("build_config_id").(string)), d.Id())); err != nil {
+		if !isNotFoundError(err) {
+			return diag.FromErr(describeAPIError(err, "Artifact Dependency"))
+		}
+	}
 
-	return dep.DeleteArtifact(d.Id())
+	return nil
 }
 
-func getArtifactDependency(c *api.DependencyService, id string) (*api.ArtifactDependency, error) {
+func artifactDependenciesPath(buildConfigID string) string {
+	return buildConfigPath(buildConfigID) + "/artifact-dependencies"
+}
 
-	dt, err := c.GetArtifactByID(id)
-	if err != nil {
+func getArtifactDependency(r *restClient, buildConfigID string, id string) (*api.ArtifactDependency, error) {
+	var out api.ArtifactDependency
+	if err := r.get(fmt.Sprintf("%s/%s", artifactDependenciesPath(buildConfigID), id), &out); err != nil {
 		return nil, err
 	}
-
-	return dt, nil
+	out.SetBuildTypeID(buildConfigID)
+	return &out, nil
 }
 
 func expandArtifactDependencyOptions(d *schema.ResourceData) (*api.ArtifactDependencyOptions, error) {
@@ -1,12 +1,14 @@
 package teamcity_test
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strings"
 	"testing"
 
 	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/cvbarros/terraform-provider-teamcity/teamcity"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -177,7 +179,7 @@ func TestAccTeamcityArtifactDependency_ConfigErrorForRevision(t *testing.T) {
 
 func testAccCheckTeamcityArtifactDependencyDestroy(bt *string, resourceType string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 		return ArtifactDependencyDestroyHelper(s, bt, client, resourceType)
 	}
 }
@@ -205,7 +207,7 @@ func ArtifactDependencyDestroyHelper(s *terraform.State, bt *string, client *api
 
 func testAccCheckTeamcityArtifactDependencyExists(n string, bt *string, snap *api.ArtifactDependency) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 		return teamcityArtifactDependencyExistsHelper(n, bt, s, client, snap)
 	}
 }
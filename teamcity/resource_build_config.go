@@ -6,25 +6,37 @@ import (
 	"fmt"
 	"log"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
 	api "github.com/cvbarros/go-teamcity/teamcity"
 	"github.com/cvbarros/terraform-provider-teamcity/internal/hashcode"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceBuildConfig() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceBuildConfigCreate,
-		Read:   resourceBuildConfigRead,
-		Update: resourceBuildConfigUpdate,
-		Delete: resourceBuildConfigDelete,
+		CreateContext: resourceBuildConfigCreate,
+		ReadContext:   resourceBuildConfigRead,
+		UpdateContext: resourceBuildConfigUpdate,
+		DeleteContext: resourceBuildConfigDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
-		CustomizeDiff: func(_ context.Context, diff *schema.ResourceDiff, v interface{}) error {
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		CustomizeDiff: customdiff.All(func(_ context.Context, diff *schema.ResourceDiff, v interface{}) error {
 			if diff.HasChange("settings") {
 				o, n := diff.GetChange("settings")
 
@@ -42,7 +54,7 @@ func resourceBuildConfig() *schema.Resource {
 					return err
 				}
 
-				if buildCounterChange(osi, nsi) {
+				if buildCounterChange(osi, nsi) && !checkoutSettingsChange(os[0], ns[0]) {
 					var setComputed bool
 
 					// If the configuration doesn't specify the build counter, set the value from READ and mark settings as computed
@@ -56,7 +68,11 @@ func resourceBuildConfig() *schema.Resource {
 						setComputed = true
 					}
 					if setComputed {
-						computed := flattenBuildConfigOptionsRaw(nsi)
+						computed := make(map[string]interface{})
+						for k, v := range ns[0].(map[string]interface{}) {
+							computed[k] = v
+						}
+						computed["build_counter"] = nsi.BuildCounter
 						err := diff.SetNew("settings", []map[string]interface{}{computed})
 						if err != nil {
 							return err
@@ -65,9 +81,10 @@ func resourceBuildConfig() *schema.Resource {
 				}
 			}
 			return nil
-		},
+		}, resourceBuildConfigStepsDiff, validateParameterSpecsDiff, resourceBuildConfigPausedDiff),
 
 		Schema: map[string]*schema.Schema{
+			"external_id": externalIDSchema(),
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -79,12 +96,20 @@ func resourceBuildConfig() *schema.Resource {
 			"project_id": {
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
 			},
 			"description": {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			"paused": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"paused_comment": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
 			"vcs_root": {
 				Type:     schema.TypeSet,
 				Optional: true,
@@ -116,13 +141,45 @@ func resourceBuildConfig() *schema.Resource {
 						"type": {
 							Type:         schema.TypeString,
 							Required:     true,
-							ValidateFunc: validation.StringInSlice([]string{"powershell", "cmd_line"}, false),
+							ValidateFunc: validation.StringInSlice([]string{"powershell", "cmd_line", "gradle", "maven", "dotnet", "docker", "kotlin_script", "generic"}, false),
 						},
 						"name": {
 							Type:     schema.TypeString,
 							Optional: true,
 							Computed: true,
 						},
+						"enabled": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+						"execute_mode": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      api.StepExecuteModeDefault,
+							ValidateFunc: validation.StringInSlice(stepExecuteModes, false),
+						},
+						"condition": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"type": {
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validation.StringInSlice(api.ConditionStrings, false),
+									},
+									"name": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"value": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+								},
+							},
+						},
 						"file": {
 							Type:     schema.TypeString,
 							Optional: true,
@@ -135,6 +192,72 @@ func resourceBuildConfig() *schema.Resource {
 							Type:     schema.TypeString,
 							Optional: true,
 						},
+						"tasks": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"goals": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"build_file": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"use_wrapper": {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+						"jdk_home": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"command": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"projects": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"configuration": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"sub_command": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"context_dir": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"image_names": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"platform": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice([]string{"linux", "windows"}, false),
+						},
+						"remove_image": {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+						"kotlin_path": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"runner_type": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"parameters": {
+							Type:     schema.TypeMap,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
 					},
 				},
 			},
@@ -150,6 +273,12 @@ func resourceBuildConfig() *schema.Resource {
 				Type:     schema.TypeMap,
 				Optional: true,
 			},
+			"param": parameterSpecSchema(),
+			"inherited_params": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
 			"settings": {
 				Type:       schema.TypeList,
 				Optional:   true,
@@ -201,6 +330,27 @@ func resourceBuildConfig() *schema.Resource {
 							ValidateFunc: validation.IntAtLeast(0),
 							Default:      0,
 						},
+						"checkout_mode": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validation.StringInSlice([]string{"ON_AGENT", "ON_SERVER", "MANUAL"}, false),
+						},
+						"checkout_dir": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "",
+						},
+						"clean_build": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						"show_dependency_changes": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
 					},
 				},
 			},
@@ -209,6 +359,88 @@ func resourceBuildConfig() *schema.Resource {
 				Elem:     &schema.Schema{Type: schema.TypeString},
 				Optional: true,
 			},
+			"disabled_inherited": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"steps": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"triggers": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"features": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"agent_requirements": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			"inherited_step_params": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"step_id": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"params": {
+							Type:     schema.TypeMap,
+							Required: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			"failure_conditions": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"execution_timeout": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      0,
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+						"fail_on_exit_code": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+						"fail_on_error_message": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						"fail_on_tests_failed": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+						"fail_on_crash": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+					},
+				},
+			},
 		},
 		SchemaVersion: 1,
 		StateUpgraders: []schema.StateUpgrader{
@@ -232,63 +464,46 @@ func buildCounterChange(o *api.BuildTypeOptions, n *api.BuildTypeOptions) bool {
 		o.BuildCounter != n.BuildCounter
 }
 
-func resourceBuildConfigCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
-	var projectID, name string
-	isTemplate := false
-
-	if err := validateBuildConfig(d); err != nil {
-		return err
-	}
-
-	if v, ok := d.GetOk("project_id"); ok {
-		projectID = v.(string)
-	}
-
-	if v, ok := d.GetOk("name"); ok {
-		name = v.(string)
-	}
-
-	log.Printf("[DEBUG] resourceBuildConfigCreate: starting create for build configuration named '%v'.", name)
-
-	var bt *api.BuildType
-	var err error
-
-	if v, ok := d.GetOk("is_template"); ok {
-		log.Printf("[DEBUG] resourceBuildConfigCreate: setting is_template = '%v'.", v.(bool))
-		isTemplate = v.(bool)
+// checkoutSettingsChange returns whether the checkout and clean build settings, which go-teamcity doesn't support,
+// changed between two `settings` blocks
+func checkoutSettingsChange(o, n interface{}) bool {
+	om, _ := o.(map[string]interface{})
+	nm, _ := n.(map[string]interface{})
+	for _, attribute := range []string{"checkout_mode", "checkout_dir", "clean_build", "show_dependency_changes"} {
+		if formatBuildConfigSetting(om[attribute]) != formatBuildConfigSetting(nm[attribute]) {
+			return true
+		}
 	}
+	return false
+}
 
-	if isTemplate {
-		bt, err = api.NewBuildTypeTemplate(projectID, name)
-	} else {
-		bt, err = api.NewBuildType(projectID, name)
-	}
-	if err != nil {
-		return err
-	}
+// newBuildConfig is the payload creating a Build Configuration. Its description, parameters, settings and the rest are
+// then written by resourceBuildConfigUpdate, so that only the settings set in the configuration override the defaults
+// of TeamCity or the ones inherited from templates.
+type newBuildConfig struct {
+	ID           string `json:"id,omitempty"`
+	Name         string `json:"name"`
+	ProjectID    string `json:"projectId"`
+	TemplateFlag bool   `json:"templateFlag"`
+}
 
-	//BuildType templates don't support description
-	if v, ok := d.GetOk("description"); ok && !isTemplate {
-		bt.Description = v.(string)
+func resourceBuildConfigCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if diags := validateBuildConfig(d); diags.HasError() {
+		return diags
 	}
 
-	bt.Parameters, err = expandParameterCollection(d)
-	if err != nil {
-		return err
+	bt := newBuildConfig{
+		ID:           d.Get("external_id").(string),
+		Name:         d.Get("name").(string),
+		ProjectID:    d.Get("project_id").(string),
+		TemplateFlag: d.Get("is_template").(bool),
 	}
 
-	opt, err := expandBuildConfigOptions(d)
-	if err != nil {
-		return err
-	}
-	if opt != nil {
-		bt.Options = opt
-	}
+	log.Printf("[DEBUG] resourceBuildConfigCreate: starting create for build configuration named '%v'.", bt.Name)
 
-	created, err := client.BuildTypes.Create(bt)
-	if err != nil {
-		return err
+	var created api.BuildTypeReference
+	if err := meta.(*Client).rest(ctx).post("buildTypes", bt, &created); err != nil {
+		return diag.FromErr(describeAPIError(err, "Build Configuration"))
 	}
 
 	log.Printf("[DEBUG] resourceBuildConfigCreate: sucessfully created build configuration with id = '%v'. Marking new resource.", created.ID)
@@ -298,66 +513,96 @@ func resourceBuildConfigCreate(d *schema.ResourceData, meta interface{}) error {
 
 	log.Printf("[DEBUG] resourceBuildConfigCreate: initial creation finished. Calling resourceBuildConfigUpdate to update the rest of resource.")
 
-	return resourceBuildConfigUpdate(d, meta)
+	return resourceBuildConfigUpdate(ctx, d, meta)
 }
 
-func resourceBuildConfigUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
-	dt, err := getBuildConfiguration(client, d.Id())
+func resourceBuildConfigUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
+	unlock, err := meta.(*Client).lockBuildType(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
+
+	r := meta.(*Client).rest(ctx)
 	log.Printf("[DEBUG] resourceBuildConfigUpdate started for resouceId: %v", d.Id())
 
-	if err != nil {
-		return err
+	if v, ok := d.GetOk("external_id"); ok && v.(string) != d.Id() {
+		log.Printf("[DEBUG] resourceBuildConfigUpdate: changing ID to '%v'", v)
+		if err := changeExternalID(r, buildConfigPath(d.Id()), v.(string)); err != nil {
+			return diag.FromErr(describeAPIError(err, "Build Configuration"))
+		}
+		meta.(*Client).invalidateBuildType(d.Id())
+		d.SetId(v.(string))
 	}
 
-	if d.HasChange("name") {
-		dt.Name = d.Get("name").(string)
+	if d.HasChange("project_id") && !d.IsNewResource() {
+		projectID := d.Get("project_id").(string)
+		dt, err := meta.(*Client).getBuildType(ctx, d.Id())
+		if err != nil {
+			return diag.FromErr(describeAPIError(err, "Build Configuration"))
+		}
+		// the Project may only have had its ID changed
+		if dt.ProjectID != projectID {
+			log.Printf("[DEBUG] resourceBuildConfigUpdate: moving build configuration to project '%v'", projectID)
+			if err := moveBuildConfig(r, d.Id(), projectID); err != nil {
+				return diag.FromErr(describeAPIError(err, "Build Configuration"))
+			}
+		}
 	}
 
-	var changed bool
-	if d.HasChange("sys_params") || d.HasChange("config_params") || d.HasChange("env_params") {
+	// go-teamcity replaces all the settings when updating a Build Configuration, which would override the ones
+	// inherited from templates, and doesn't support parameter specifications, so the changes are applied one by one
+	if d.HasChange("name") {
+		if err := r.putText(buildConfigPath(d.Id())+"/name", d.Get("name").(string)); err != nil {
+			return diag.FromErr(describeAPIError(err, "Build Configuration"))
+		}
+	}
+	if v, ok := d.GetOk("description"); ok && d.HasChange("description") {
+		log.Printf("[DEBUG] resourceBuildConfigUpdate: change detected for description")
+		if err := r.putText(buildConfigPath(d.Id())+"/description", v.(string)); err != nil {
+			return diag.FromErr(describeAPIError(err, "Build Configuration"))
+		}
+	}
+	if d.HasChange("paused") || d.HasChange("paused_comment") {
+		log.Printf("[DEBUG] resourceBuildConfigUpdate: change detected for paused state")
+		if err := setBuildConfigPaused(r, d.Id(), d.Get("paused").(bool), d.Get("paused_comment").(string)); err != nil {
+			return diag.FromErr(describeAPIError(err, "Build Configuration"))
+		}
+	}
+	if d.HasChange("sys_params") || d.HasChange("config_params") || d.HasChange("env_params") || d.HasChange("param") {
 		log.Printf("[DEBUG] resourceBuildConfigUpdate: change detected for params")
-		dt.Parameters, err = expandParameterCollection(d)
+		params, err := expandParameterSpecs(d)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
-		changed = true
-	}
-	if v, ok := d.GetOk("description"); ok {
-		if d.HasChange("description") {
-			log.Printf("[DEBUG] resourceBuildConfigUpdate: change detected for description")
-			dt.Description = v.(string)
-			changed = true
+		if err := setParameters(r, buildConfigPath(d.Id()), params); err != nil {
+			return diag.FromErr(describeAPIError(err, "Build Configuration"))
 		}
 	}
 	if d.HasChange("settings") {
 		log.Printf("[DEBUG] resourceBuildConfigUpdate: change detected for settings")
-		if _, ok := d.GetOk("settings"); ok {
-			dt.Options, err = expandBuildConfigOptions(d)
-			if err != nil {
-				return err
-			}
-			changed = true
+		o, n := d.GetChange("settings")
+		if err := updateBuildConfigSettings(r, d.Id(), optionSettings, o.([]interface{}), n.([]interface{}), configuredAttributes(d, "settings"), d.Get("is_template").(bool)); err != nil {
+			return diag.FromErr(describeAPIError(err, "Build Configuration"))
 		}
 	}
-
-	if changed {
-		_, err := client.BuildTypes.Update(dt)
-		if err != nil {
-			return err
+	if d.HasChange("failure_conditions") {
+		log.Printf("[DEBUG] resourceBuildConfigUpdate: change detected for failure conditions")
+		o, n := d.GetChange("failure_conditions")
+		if err := updateBuildConfigSettings(r, d.Id(), failureConditionSettings, o.([]interface{}), n.([]interface{}), configuredAttributes(d, "failure_conditions"), false); err != nil {
+			return diag.FromErr(describeAPIError(err, "Build Configuration"))
 		}
 	}
 
-	if v, ok := d.GetOk("vcs_root"); ok {
-		vcs := v.(*schema.Set).List()
-		for _, raw := range vcs {
-			toAttach := buildVcsRootEntry(raw)
+	if d.HasChange("vcs_root") {
+		log.Printf("[DEBUG] resourceBuildConfigUpdate: change detected for vcs roots")
+		o, n := d.GetChange("vcs_root")
+		from := buildVcsRootEntries(o.(*schema.Set).List())
+		to := buildVcsRootEntries(n.(*schema.Set).List())
 
-			err := client.BuildTypes.AttachVcsRootEntry(dt.ID, toAttach)
-			if err != nil {
-				return err
-			}
-			log.Printf("[DEBUG] resourceBuildConfigUpdate: attached vcsRoot '%v' to build configuration", toAttach.ID)
+		if err := updateVcsRootEntries(r, d.Id(), from, to); err != nil {
+			return diag.FromErr(describeAPIError(err, "Build Configuration"))
 		}
 	}
 
@@ -367,27 +612,14 @@ func resourceBuildConfigUpdate(d *schema.ResourceData, meta interface{}) error {
 		os := o.([]interface{})
 		ns := n.([]interface{})
 
-		remove, _ := expandBuildSteps(os)
-		add, err := expandBuildSteps(ns)
+		from, _ := expandBuildSteps(os, meta.(*Client))
+		to, err := expandBuildSteps(ns, meta.(*Client))
 
 		if err != nil {
-			return err
-		}
-		if len(remove) > 0 {
-			for _, s := range remove {
-				err := client.BuildTypes.DeleteStep(dt.ID, s.GetID())
-				if err != nil {
-					return err
-				}
-			}
+			return diag.FromErr(err)
 		}
-		if len(add) > 0 {
-			for _, s := range add {
-				_, err := client.BuildTypes.AddStep(dt.ID, s)
-				if err != nil {
-					return err
-				}
-			}
+		if err := updateBuildSteps(r, d.Id(), from, to); err != nil {
+			return diag.FromErr(describeAPIError(err, "Build Configuration"))
 		}
 	}
 
@@ -399,34 +631,63 @@ func resourceBuildConfigUpdate(d *schema.ResourceData, meta interface{}) error {
 			_, err := buildTemplateService.Attach(a)
 			log.Printf("[DEBUG] resourceBuildConfigUpdate: attached template '%v' to build configuration", a)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 		}
 		for _, r := range remove {
 			err := buildTemplateService.Detach(r)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			log.Printf("[DEBUG] resourceBuildConfigUpdate: detached template '%v' from build configuration", r)
 		}
 	}
 
+	// the items inherited from the templates are disabled once the templates are attached
+	if d.HasChange("disabled_inherited") || d.HasChange("templates") {
+		log.Printf("[DEBUG] resourceBuildConfigUpdate: change detected for disabled inherited items")
+		o, n := d.GetChange("disabled_inherited")
+		if err := updateDisabledInherited(r, d.Id(), o.([]interface{}), n.([]interface{})); err != nil {
+			return diag.FromErr(describeAPIError(err, "Build Configuration"))
+		}
+	}
+
+	if d.HasChange("inherited_step_params") || d.HasChange("templates") {
+		log.Printf("[DEBUG] resourceBuildConfigUpdate: change detected for inherited step parameters")
+		o, n := d.GetChange("inherited_step_params")
+		templates := expandStringSlice(d.Get("templates").([]interface{}))
+		if err := updateInheritedStepParams(r, d.Id(), templates, o.(*schema.Set).List(), n.(*schema.Set).List()); err != nil {
+			return diag.FromErr(describeAPIError(err, "Build Configuration"))
+		}
+	}
+
 	d.Partial(false)
 	log.Printf("[DEBUG] resourceBuildConfigUpdate: updated finished. Calling 'read' to refresh state.")
-	return resourceBuildConfigRead(d, meta)
+	// the lock only invalidates the cached Build Configuration once the read has completed
+	meta.(*Client).invalidateBuildType(d.Id())
+	return resourceBuildConfigRead(ctx, d, meta)
 }
 
-func resourceBuildConfigDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceBuildConfigDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
 	log.Printf("[DEBUG] resourceBuildConfigDelete: destroying build configuration '%v'.", d.Id())
-	return client.BuildTypes.Delete(d.Id())
-}
+	unlock, err := meta.(*Client).lockBuildType(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
+	if err := client.BuildTypes.Delete(d.Id()); err != nil {
+		if !isNotFoundError(err) {
+			return diag.FromErr(describeAPIError(err, "Build Configuration"))
+		}
+	}
 
-func resourceBuildConfigRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+	return nil
+}
 
+func resourceBuildConfigRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] resourceBuildConfigRead started for resouceId: %v", d.Id())
-	dt, err := getBuildConfiguration(client, d.Id())
+	dt, err := meta.(*Client).getBuildType(ctx, d.Id())
 	if err != nil {
 		// handles this being deleted outside of TF
 		if isNotFoundError(err) {
@@ -435,102 +696,135 @@ func resourceBuildConfigRead(d *schema.ResourceData, meta interface{}) error {
 			return nil
 		}
 
-		return err
+		return diag.FromErr(describeAPIError(err, "Build Configuration"))
 	}
 	log.Printf("[DEBUG] BuildConfiguration '%v' retrieved successfully", dt.Name)
+	if err := d.Set("external_id", dt.ID); err != nil {
+		return diag.FromErr(err)
+	}
 	if err := d.Set("name", dt.Name); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("is_template", dt.IsTemplate); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	//description not supported for templates.
 	if !dt.IsTemplate {
 		if err := d.Set("description", dt.Description); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 	if err := d.Set("project_id", dt.ProjectID); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	err = flattenParameterCollection(d, dt.Parameters)
+	pause, err := getBuildConfigPause(meta.(*Client).rest(ctx), d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(describeAPIError(err, "Build Configuration"))
 	}
-	err = flattenBuildConfigOptions(d, dt.Options)
+	if err := flattenBuildConfigPause(d, pause); err != nil {
+		return diag.FromErr(err)
+	}
+	params, err := getParameters(meta.(*Client).rest(ctx), buildConfigPath(d.Id()))
 	if err != nil {
-		return err
+		return diag.FromErr(describeAPIError(err, "Build Configuration"))
+	}
+	if err := flattenParameterSpecs(d, params); err != nil {
+		return diag.FromErr(err)
+	}
+	settings, err := getBuildConfigSettings(meta.(*Client).rest(ctx), d.Id())
+	if err != nil {
+		return diag.FromErr(describeAPIError(err, "Build Configuration"))
+	}
+	if err := flattenBuildConfigOptions(d, settings, dt.IsTemplate); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := flattenBuildConfigFailureConditions(d, settings); err != nil {
+		return diag.FromErr(err)
 	}
 	err = flattenTemplates(d, dt.Templates)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-
-	vcsRoots := dt.VcsRootEntries
-
-	if len(vcsRoots) > 0 {
-		var vcsToSave []map[string]interface{}
-		for _, el := range vcsRoots {
-			m := make(map[string]interface{})
-			m["id"] = el.ID
-			m["checkout_rules"] = strings.Split(el.CheckoutRules, "\\n")
-			vcsToSave = append(vcsToSave, m)
+	// without templates, nothing can be inherited, let alone disabled
+	var disabled []map[string]interface{}
+	if dt.Templates != nil && len(dt.Templates.Items) > 0 {
+		items, err := meta.(*Client).getBuildTypeInheritableItems(ctx, d.Id())
+		if err != nil {
+			return diag.FromErr(describeAPIError(err, "Build Configuration"))
 		}
+		disabled = flattenDisabledInherited(items)
+	}
+	if err := d.Set("disabled_inherited", disabled); err != nil {
+		return diag.FromErr(err)
+	}
 
-		if err := d.Set("vcs_root", vcsToSave); err != nil {
-			return err
-		}
+	vcsToSave := make([]map[string]interface{}, 0, len(dt.VcsRootEntries))
+	for _, el := range dt.VcsRootEntries {
+		m := make(map[string]interface{})
+		m["id"] = el.ID
+		m["checkout_rules"] = flattenCheckoutRules(el.CheckoutRules)
+		vcsToSave = append(vcsToSave, m)
+	}
+	// VCS Roots detached outside of TF are removed from the state
+	if err := d.Set("vcs_root", vcsToSave); err != nil {
+		return diag.FromErr(err)
 	}
 
-	steps, err := client.BuildTypes.GetSteps(d.Id())
+	steps, err := getBuildSteps(meta.(*Client).rest(ctx), d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(describeAPIError(err, "Build Configuration"))
 	}
 	if len(steps) > 0 {
 		var stepsToSave []map[string]interface{}
 		for _, el := range steps {
-			if el != nil {
+			// steps inherited from templates are managed by the templates
+			if el != nil && !isInherited(el) {
 				l, err := flattenBuildStep(el)
 				if err != nil {
-					return err
+					return diag.FromErr(err)
 				}
 				stepsToSave = append(stepsToSave, l)
 			}
 		}
 
 		if err := d.Set("step", stepsToSave); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
+	if err := d.Set("inherited_step_params", flattenInheritedStepParams(steps, d.Get("inherited_step_params").(*schema.Set).List())); err != nil {
+		return diag.FromErr(err)
+	}
 
 	return nil
 }
 
-func validateBuildConfig(d *schema.ResourceData) error {
+func validateBuildConfig(d *schema.ResourceData) diag.Diagnostics {
 	if v, ok := d.GetOk("is_template"); ok {
 		isTemplate := v.(bool)
 
 		if isTemplate {
 			if _, isSet := d.GetOk("description"); isSet {
-				return fmt.Errorf("'description' field is not supported for Build Configuration Templates. See issue https://youtrack.jetbrains.com/issue/TW-63617 for details")
+				return diag.Diagnostics{attributeError("description",
+					"'description' field is not supported for Build Configuration Templates. See issue https://youtrack.jetbrains.com/issue/TW-63617 for details", "")}
 			}
 		}
 	}
 	return nil
 }
 
-func getBuildConfiguration(c *api.Client, id string) (*api.BuildType, error) {
-	dt, err := c.BuildTypes.GetByID(id)
-	if err != nil {
-		return nil, err
+// resourceBuildConfigStepsDiff checks each step only sets the attributes supported by its type, so mistakes are
+// reported at plan time
+func resourceBuildConfigStepsDiff(_ context.Context, diff *schema.ResourceDiff, _ interface{}) error {
+	for i, raw := range diff.Get("step").([]interface{}) {
+		dt, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if err := validateBuildStep(i, dt); err != nil {
+			return err
+		}
 	}
-
-	return dt, nil
-}
-
-var stepTypeMap = map[string]string{
-	api.StepTypePowershell:  "powershell",
-	api.StepTypeCommandLine: "cmd_line",
+	return nil
 }
 
 func flattenTemplates(d *schema.ResourceData, templates *api.Templates) error {
@@ -549,103 +843,6 @@ func flattenTemplates(d *schema.ResourceData, templates *api.Templates) error {
 	return nil
 }
 
-func flattenParameterCollection(d *schema.ResourceData, params *api.Parameters) error {
-	configParams := flattenParameters(params, api.ParameterTypes.Configuration)
-	if err := d.Set("config_params", configParams); err != nil {
-		return err
-	}
-
-	envParams := flattenParameters(params, api.ParameterTypes.EnvironmentVariable)
-	if err := d.Set("env_params", envParams); err != nil {
-		return err
-	}
-
-	systemParams := flattenParameters(params, api.ParameterTypes.System)
-	if err := d.Set("sys_params", systemParams); err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func expandParameterCollection(d *schema.ResourceData) (*api.Parameters, error) {
-	var config, system, env *api.Parameters
-	if v, ok := d.GetOk("env_params"); ok {
-		p, err := expandParameters(v.(map[string]interface{}), api.ParameterTypes.EnvironmentVariable)
-		if err != nil {
-			return nil, err
-		}
-		env = p
-	}
-
-	if v, ok := d.GetOk("sys_params"); ok {
-		p, err := expandParameters(v.(map[string]interface{}), api.ParameterTypes.System)
-		if err != nil {
-			return nil, err
-		}
-		system = p
-	}
-
-	if v, ok := d.GetOk("config_params"); ok {
-		p, err := expandParameters(v.(map[string]interface{}), api.ParameterTypes.Configuration)
-		if err != nil {
-			return nil, err
-		}
-		config = p
-	}
-
-	out := api.NewParametersEmpty()
-
-	if config != nil {
-		out = out.Concat(config)
-	}
-	if system != nil {
-		out = out.Concat(system)
-	}
-	if env != nil {
-		out = out.Concat(env)
-	}
-	return out, nil
-}
-
-func flattenParameters(input *api.Parameters, paramType string) map[string]string {
-	output := make(map[string]string)
-	if input == nil {
-		return output
-	}
-
-	for _, p := range input.Items {
-		if p.Type != paramType {
-			continue
-		}
-
-		output[p.Name] = p.Value
-	}
-
-	return output
-}
-
-func expandParameters(raw map[string]interface{}, paramType string) (*api.Parameters, error) {
-	out := api.NewParametersEmpty()
-	for k, v := range raw {
-		p, err := api.NewParameter(paramType, k, v.(string))
-		if err != nil {
-			return nil, err
-		}
-		out.AddOrReplaceParameter(p)
-	}
-	return out, nil
-}
-
-func expandBuildConfigOptions(d *schema.ResourceData) (*api.BuildTypeOptions, error) {
-	v, ok := d.GetOk("settings")
-	if !ok {
-		return nil, nil
-	}
-
-	return expandBuildConfigOptionsRaw(v.([]interface{}))
-}
-
 func expandBuildConfigOptionsRaw(v []interface{}) (*api.BuildTypeOptions, error) {
 	raw := v[0].(map[string]interface{})
 	opt := api.NewBuildTypeOptionsWithDefaults()
@@ -677,40 +874,257 @@ func expandBuildConfigOptionsRaw(v []interface{}) (*api.BuildTypeOptions, error)
 
 	return opt, nil
 }
-func flattenBuildConfigOptions(d *schema.ResourceData, dt *api.BuildTypeOptions) error {
-	m := flattenBuildConfigOptionsRaw(dt)
-	return d.Set("settings", []map[string]interface{}{m})
+
+// failureConditionSettings maps the attributes of `failure_conditions` to the settings of a Build Configuration
+var failureConditionSettings = map[string]string{
+	"execution_timeout":     "executionTimeoutMin",
+	"fail_on_exit_code":     "shouldFailBuildOnBadExitCode",
+	"fail_on_error_message": "shouldFailBuildOnAnyErrorMessage",
+	"fail_on_tests_failed":  "shouldFailBuildIfTestsFailed",
+	"fail_on_crash":         "shouldFailBuildOnOOMEOrCrash",
 }
 
-func flattenBuildConfigOptionsRaw(dt *api.BuildTypeOptions) map[string]interface{} {
-	m := make(map[string]interface{})
+// optionSettings maps the attributes of `settings` to the settings of a Build Configuration
+var optionSettings = map[string]string{
+	"configuration_type":      "buildConfigurationType",
+	"build_number_format":     "buildNumberPattern",
+	"build_counter":           "buildNumberCounter",
+	"allow_personal_builds":   "allowPersonalBuildTriggering",
+	"artifact_paths":          "artifactRules",
+	"detect_hanging":          "enableHangingBuildsDetection",
+	"status_widget":           "allowExternalStatus",
+	"concurrent_limit":        "maximumNumberOfBuilds",
+	"checkout_mode":           "checkoutMode",
+	"checkout_dir":            "checkoutDirectory",
+	"clean_build":             "cleanBuild",
+	"show_dependency_changes": "showDependenciesChanges",
+}
 
-	m["configuration_type"] = dt.BuildConfigurationType
-	m["build_number_format"] = dt.BuildNumberFormat
-	m["build_counter"] = dt.BuildCounter
-	m["allow_personal_builds"] = dt.AllowPersonalBuildTriggering
-	m["artifact_paths"] = flattenStringSlice(dt.ArtifactRules)
-	m["detect_hanging"] = dt.EnableHangingBuildsDetection
-	m["status_widget"] = dt.EnableStatusWidget
-	m["concurrent_limit"] = dt.MaxSimultaneousBuilds
+// computedSettings are the settings left to TeamCity when their attribute isn't known, mapped to the value the
+// attribute has then
+var computedSettings = map[string]string{
+	"buildNumberCounter": "0",
+	"checkoutMode":       "",
+}
 
-	return m
+// buildConfigSetting is a setting of a Build Configuration, which is inherited when it's set by one of its templates
+type buildConfigSetting struct {
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	Inherited *bool  `json:"inherited,omitempty"`
+}
+
+type buildConfigSettings struct {
+	Count int                   `json:"count"`
+	Items []*buildConfigSetting `json:"property"`
+}
+
+func getBuildConfigSettings(r *restClient, id string) (map[string]*buildConfigSetting, error) {
+	var out buildConfigSettings
+	if err := r.get(buildConfigPath(id)+"/settings", &out); err != nil {
+		return nil, err
+	}
+	settings := make(map[string]*buildConfigSetting, len(out.Items))
+	for _, s := range out.Items {
+		settings[s.Name] = s
+	}
+	return settings, nil
+}
+
+// updateBuildConfigSettings sets each setting of a block which changed separately, so the other settings are kept,
+// and settings inherited from a template remain inherited until they're changed. Without a prior value, e.g. when the
+// Build Configuration is created, only the settings set in the configuration are written, rather than their defaults.
+func updateBuildConfigSettings(r *restClient, id string, names map[string]string, o, n []interface{}, configured map[string]bool, isTemplate bool) error {
+	if len(n) == 0 || n[0] == nil {
+		return nil
+	}
+	from := make(map[string]interface{})
+	if len(o) > 0 && o[0] != nil {
+		from = o[0].(map[string]interface{})
+	}
+	to := n[0].(map[string]interface{})
+
+	for attribute, setting := range names {
+		value := formatBuildConfigSetting(to[attribute])
+		prior, ok := from[attribute]
+		if ok && formatBuildConfigSetting(prior) == value {
+			continue
+		}
+		if !ok && !configured[attribute] {
+			continue
+		}
+		// templates don't have a build counter
+		if setting == "buildNumberCounter" && isTemplate {
+			continue
+		}
+		if unknown, ok := computedSettings[setting]; ok && value == unknown {
+			continue
+		}
+		if err := r.putText(fmt.Sprintf("%s/settings/%s", buildConfigPath(id), setting), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// configuredAttributes returns the attributes of a block with a single item which are set in the configuration
+func configuredAttributes(d *schema.ResourceData, block string) map[string]bool {
+	out := make(map[string]bool)
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return out
+	}
+	list := config.GetAttr(block)
+	if list.IsNull() || !list.IsKnown() || list.LengthInt() == 0 {
+		return out
+	}
+	item := list.Index(cty.NumberIntVal(0))
+	if item.IsNull() || !item.IsKnown() {
+		return out
+	}
+	for attribute := range item.Type().AttributeTypes() {
+		if !item.GetAttr(attribute).IsNull() {
+			out[attribute] = true
+		}
+	}
+	return out
 }
 
-func flattenBuildStep(s api.Step) (map[string]interface{}, error) {
-	mapType := stepTypeMap[s.Type()]
+func formatBuildConfigSetting(v interface{}) string {
+	switch v := v.(type) {
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		return v
+	case []interface{}:
+		return strings.Join(expandStringSlice(v), "\n")
+	}
+	return ""
+}
+
+// flattenBuildConfigSettings reads the attributes of a block from the settings of a Build Configuration, which TeamCity
+// omits when they have their default value. Settings inherited from a template aren't managed by the Build
+// Configuration, so the values of the prior state are kept for them.
+func flattenBuildConfigSettings(names map[string]string, defaults map[string]interface{}, settings map[string]*buildConfigSetting, prior []interface{}) []map[string]interface{} {
+	previous := make(map[string]interface{})
+	if len(prior) > 0 && prior[0] != nil {
+		previous = prior[0].(map[string]interface{})
+	}
+
+	m := make(map[string]interface{}, len(defaults))
+	for attribute, v := range defaults {
+		m[attribute] = v
+	}
+	for attribute, setting := range names {
+		s, ok := settings[setting]
+		if !ok {
+			continue
+		}
+		if v, ok := previous[attribute]; ok && s.Inherited != nil && *s.Inherited {
+			m[attribute] = v
+			continue
+		}
+		switch m[attribute].(type) {
+		case int:
+			if i, err := strconv.Atoi(s.Value); err == nil {
+				m[attribute] = i
+			}
+		case bool:
+			m[attribute] = s.Value == "true"
+		case string:
+			m[attribute] = s.Value
+		case []interface{}:
+			m[attribute] = flattenStringSlice(strings.Split(s.Value, "\n"))
+		}
+	}
+	return []map[string]interface{}{m}
+}
+
+func flattenBuildConfigOptions(d *schema.ResourceData, settings map[string]*buildConfigSetting, isTemplate bool) error {
+	defaults := map[string]interface{}{
+		"configuration_type":      api.DefaultBuildConfigurationType,
+		"build_number_format":     api.DefaultBuildNumberFormat,
+		"build_counter":           0,
+		"allow_personal_builds":   true,
+		"artifact_paths":          []interface{}{},
+		"detect_hanging":          true,
+		"status_widget":           false,
+		"concurrent_limit":        0,
+		"checkout_mode":           "ON_AGENT",
+		"checkout_dir":            "",
+		"clean_build":             false,
+		"show_dependency_changes": false,
+	}
+	names := optionSettings
+	if isTemplate {
+		names = make(map[string]string)
+		for attribute, setting := range optionSettings {
+			if attribute != "build_counter" {
+				names[attribute] = setting
+			}
+		}
+	}
+	return d.Set("settings", flattenBuildConfigSettings(names, defaults, settings, d.Get("settings").([]interface{})))
+}
+
+func flattenBuildConfigFailureConditions(d *schema.ResourceData, settings map[string]*buildConfigSetting) error {
+	defaults := map[string]interface{}{
+		"execution_timeout":     0,
+		"fail_on_exit_code":     true,
+		"fail_on_error_message": false,
+		"fail_on_tests_failed":  true,
+		"fail_on_crash":         true,
+	}
+	return d.Set("failure_conditions", flattenBuildConfigSettings(failureConditionSettings, defaults, settings, d.Get("failure_conditions").([]interface{})))
+}
+
+func flattenBuildStep(s *buildStep) (map[string]interface{}, error) {
 	var out map[string]interface{}
-	var err error
-	switch mapType {
-	case "powershell":
-		out, err = flattenBuildStepPowershell(s.(*api.StepPowershell)), nil
-	case "cmd_line":
-		out, err = flattenBuildStepCmdLine(s.(*api.StepCommandLine)), nil
+	switch s.Type {
+	case api.StepTypePowershell:
+		var ps api.StepPowershell
+		if err := convertBuildStep(s, &ps); err != nil {
+			return nil, err
+		}
+		out = flattenBuildStepPowershell(&ps)
+	case api.StepTypeCommandLine:
+		var cmd api.StepCommandLine
+		if err := convertBuildStep(s, &cmd); err != nil {
+			return nil, err
+		}
+		out = flattenBuildStepCmdLine(&cmd)
+	case stepTypeGradle:
+		out = flattenBuildStepGradle(s)
+	case stepTypeMaven:
+		out = flattenBuildStepMaven(s)
+	case stepTypeDotnet, stepTypeDotnetLegacy:
+		out = flattenBuildStepDotnet(s)
+	case stepTypeDocker:
+		out = flattenBuildStepDocker(s)
+	case stepTypeKotlinScript:
+		out = flattenBuildStepKotlinScript(s)
 	default:
-		return nil, fmt.Errorf("build step type '%s' not supported", s.Type())
+		// steps using any other runner, such as meta-runners or runners provided by plugins, are kept as they are
+		out = flattenBuildStepGeneric(s)
 	}
-	out["step_id"] = s.GetID()
-	return out, err
+	out["step_id"] = s.ID
+	out["enabled"] = !isDisabled(s)
+
+	props := propertiesMap(s.Properties)
+	out["execute_mode"] = api.StepExecuteModeDefault
+	if v := props[stepPropertyExecuteMode]; v != "" {
+		out["execute_mode"] = v
+	}
+	if v := props[stepPropertyConditions]; v != "" {
+		conditions, err := parseStepConditions(v)
+		if err != nil {
+			return nil, err
+		}
+		out["condition"] = flattenStepConditions(conditions)
+	}
+	return out, nil
 }
 
 func flattenBuildStepPowershell(s *api.StepPowershell) map[string]interface{} {
@@ -751,11 +1165,11 @@ func flattenBuildStepCmdLine(s *api.StepCommandLine) map[string]interface{} {
 	return m
 }
 
-func expandBuildSteps(list interface{}) ([]api.Step, error) {
-	out := make([]api.Step, 0)
+func expandBuildSteps(list interface{}, client *Client) ([]*buildStep, error) {
+	out := make([]*buildStep, 0)
 	in := list.([]interface{})
 	for _, i := range in {
-		s, err := expandBuildStep(i)
+		s, err := expandBuildStep(i, client)
 		if err != nil {
 			return nil, err
 		}
@@ -764,15 +1178,67 @@ func expandBuildSteps(list interface{}) ([]api.Step, error) {
 	return out, nil
 }
 
-func expandBuildStep(raw interface{}) (api.Step, error) {
+func expandBuildStep(raw interface{}, client *Client) (*buildStep, error) {
 	localStep := raw.(map[string]interface{})
 
+	s, err := expandBuildStepRunner(localStep, client)
+	if err != nil {
+		return nil, err
+	}
+	if v, ok := localStep["enabled"]; ok && !v.(bool) {
+		disabled := true
+		s.Disabled = &disabled
+	} else {
+		s.Disabled = nil
+	}
+
+	if s.Properties == nil {
+		s.Properties = api.NewPropertiesEmpty()
+	}
+	mode := api.StepExecuteModeDefault
+	if v, ok := localStep["execute_mode"].(string); ok && v != "" {
+		mode = v
+	}
+	s.Properties.AddOrReplaceValue(stepPropertyExecuteMode, mode)
+	s.Properties.Remove(stepPropertyConditions)
+	if v, ok := localStep["condition"].([]interface{}); ok && len(v) > 0 {
+		conditions, err := formatStepConditions(expandStepConditions(v))
+		if err != nil {
+			return nil, err
+		}
+		s.Properties.AddOrReplaceValue(stepPropertyConditions, conditions)
+	}
+	return s, nil
+}
+
+func expandBuildStepRunner(localStep map[string]interface{}, client *Client) (*buildStep, error) {
+
 	t := localStep["type"].(string)
 	switch t {
 	case "powershell":
-		return expandStepPowershell(localStep)
+		s, err := expandStepPowershell(localStep)
+		if err != nil {
+			return nil, err
+		}
+		return newBuildStep(s)
 	case "cmd_line":
-		return expandStepCmdLine(localStep)
+		s, err := expandStepCmdLine(localStep)
+		if err != nil {
+			return nil, err
+		}
+		return newBuildStep(s)
+	case "gradle":
+		return expandStepGradle(localStep)
+	case "maven":
+		return expandStepMaven(localStep)
+	case "dotnet":
+		return expandStepDotnet(localStep, client)
+	case "docker":
+		return expandStepDocker(localStep)
+	case "kotlin_script":
+		return expandStepKotlinScript(localStep)
+	case "generic":
+		return expandStepGeneric(localStep)
 	default:
 		return nil, fmt.Errorf("unsupported step type '%s'", t)
 	}
@@ -844,6 +1310,14 @@ func expandStepPowershell(dt map[string]interface{}) (*api.StepPowershell, error
 	return s, nil
 }
 
+func buildVcsRootEntries(list []interface{}) []*api.VcsRootEntry {
+	out := make([]*api.VcsRootEntry, 0, len(list))
+	for _, raw := range list {
+		out = append(out, buildVcsRootEntry(raw))
+	}
+	return out
+}
+
 func buildVcsRootEntry(raw interface{}) *api.VcsRootEntry {
 	localVcs := raw.(map[string]interface{})
 	rawRules := localVcs["checkout_rules"].([]interface{})
@@ -859,6 +1333,15 @@ func buildVcsRootEntry(raw interface{}) *api.VcsRootEntry {
 	return api.NewVcsRootEntryWithRules(&api.VcsRootReference{ID: localVcs["id"].(string)}, toAttachRules)
 }
 
+// flattenCheckoutRules splits the checkout rules of a VCS Root entry, so an entry without rules has none rather than an
+// empty one
+func flattenCheckoutRules(rules string) []string {
+	if rules == "" {
+		return []string{}
+	}
+	return strings.Split(rules, "\\n")
+}
+
 func vcsRootHash(v interface{}) int {
 	raw := v.(map[string]interface{})
 	return schema.HashString(raw["id"].(string))
@@ -1038,10 +1521,18 @@ func resourceBuildConfigInstanceResourceV0() *schema.Resource {
 }
 
 func resourceBuildConfigInstanceStateUpgradeV0(_ context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
-	if raw, ok := rawState["steps"]; ok {
-		s := raw.(*schema.Set)
-		list := s.List()
-		rawState["steps"] = list
+	// steps of version 0 states are enabled and use the default execution mode, as they couldn't be disabled or have conditions
+	if steps, ok := rawState["step"].([]interface{}); ok {
+		for _, raw := range steps {
+			if step, ok := raw.(map[string]interface{}); ok {
+				if _, ok := step["enabled"]; !ok {
+					step["enabled"] = true
+				}
+				if _, ok := step["execute_mode"]; !ok {
+					step["execute_mode"] = api.StepExecuteModeDefault
+				}
+			}
+		}
 	}
 
 	return rawState, nil
@@ -1,16 +1,20 @@
 package teamcity_test
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"os"
 	"regexp"
 	"strings"
 	"testing"
 
+	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/cvbarros/terraform-provider-teamcity/teamcity"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
-
-	api "github.com/cvbarros/go-teamcity/teamcity"
 )
 
 func TestAccBuildConfig_Basic(t *testing.T) {
@@ -35,6 +39,33 @@ func TestAccBuildConfig_Basic(t *testing.T) {
 	})
 }
 
+func TestResourceBuildConfigStateUpgradeV0(t *testing.T) {
+	upgrader := teamcity.Provider().ResourcesMap["teamcity_build_config"].StateUpgraders[0]
+	rawState := map[string]interface{}{
+		"id":   "Project_Build",
+		"name": "Build",
+		"step": []interface{}{
+			map[string]interface{}{"type": "cmd_line", "name": "build", "code": "make"},
+		},
+	}
+
+	actual, err := upgrader.Upgrade(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatalf("unexpected error upgrading the state: %s", err)
+	}
+
+	step := actual["step"].([]interface{})[0].(map[string]interface{})
+	if step["enabled"] != true {
+		t.Errorf("expected the step to be enabled but got %v", step["enabled"])
+	}
+	if step["execute_mode"] != api.StepExecuteModeDefault {
+		t.Errorf("expected the step to use the default execution mode but got %v", step["execute_mode"])
+	}
+	if step["code"] != "make" {
+		t.Errorf("expected the step to be kept but got %v", step)
+	}
+}
+
 func TestAccBuildConfig_TemplateDoesNotSupportDescription(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:  func() { testAccPreCheck(t) },
@@ -160,6 +191,65 @@ func TestAccBuildConfig_UpdateOtherSetting(t *testing.T) {
 	})
 }
 
+func TestAccBuildConfig_ExternalID(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_config.build_configuration_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(TestAccBuildConfigExternalID, "Platform_Build"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					resource.TestCheckResourceAttr(resName, "id", "Platform_Build"),
+					resource.TestCheckResourceAttr(resName, "external_id", "Platform_Build"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(TestAccBuildConfigExternalID, "Platform_Release"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					resource.TestCheckResourceAttr(resName, "id", "Platform_Release"),
+					resource.TestCheckResourceAttr(resName, "external_id", "Platform_Release"),
+					resource.TestCheckResourceAttr(resName, "step.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBuildConfig_MoveProject(t *testing.T) {
+	var bc api.BuildType
+	var id string
+	resName := "teamcity_build_config.build_configuration_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(TestAccBuildConfigMoveProject, "source"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					testAccCheckResourceAttrValue(resName, "id", &id),
+					resource.TestCheckResourceAttrPair(resName, "project_id", "teamcity_project.source", "id"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(TestAccBuildConfigMoveProject, "target"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					resource.TestCheckResourceAttrPtr(resName, "id", &id),
+					resource.TestCheckResourceAttrPair(resName, "project_id", "teamcity_project.target", "id"),
+					resource.TestCheckResourceAttr(resName, "vcs_root.#", "1"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccBuildConfig_NestedProject(t *testing.T) {
 	var bc api.BuildType
 	resName := "teamcity_build_config.build_config"
@@ -463,6 +553,276 @@ func TestAccBuildConfig_StepsCmdLineUpdateSteps(t *testing.T) {
 	})
 }
 
+func TestAccBuildConfig_StepsGradleMavenDotnet(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_config.build_configuration_test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: TestAccBuildConfigStepsGradleMavenDotnet,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					resource.TestCheckResourceAttr(resName, "step.#", "3"),
+					resource.TestCheckResourceAttr(resName, "step.0.type", "gradle"),
+					resource.TestCheckResourceAttr(resName, "step.0.tasks", "clean build"),
+					resource.TestCheckResourceAttr(resName, "step.0.use_wrapper", "true"),
+					resource.TestCheckResourceAttr(resName, "step.1.type", "maven"),
+					resource.TestCheckResourceAttr(resName, "step.1.goals", "clean package"),
+					resource.TestCheckResourceAttr(resName, "step.1.build_file", "service/pom.xml"),
+					resource.TestCheckResourceAttr(resName, "step.2.type", "dotnet"),
+					resource.TestCheckResourceAttr(resName, "step.2.command", "test"),
+					resource.TestCheckResourceAttr(resName, "step.2.configuration", "Release"),
+				),
+			},
+			{
+				Config: TestAccBuildConfigStepsGradleMavenDotnetUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "step.#", "2"),
+					resource.TestCheckResourceAttr(resName, "step.0.tasks", "clean check"),
+					resource.TestCheckResourceAttr(resName, "step.0.use_wrapper", "false"),
+					resource.TestCheckResourceAttr(resName, "step.1.type", "dotnet"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBuildConfig_StepsDockerKotlinScript(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_config.build_configuration_test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: TestAccBuildConfigStepsDockerKotlinScript,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					resource.TestCheckResourceAttr(resName, "step.#", "3"),
+					resource.TestCheckResourceAttr(resName, "step.0.type", "docker"),
+					resource.TestCheckResourceAttr(resName, "step.0.command", "build"),
+					resource.TestCheckResourceAttr(resName, "step.0.file", "docker/Dockerfile"),
+					resource.TestCheckResourceAttr(resName, "step.0.image_names", "example/app:%build.number%"),
+					resource.TestCheckResourceAttr(resName, "step.0.platform", "linux"),
+					resource.TestCheckResourceAttr(resName, "step.1.command", "push"),
+					resource.TestCheckResourceAttr(resName, "step.1.remove_image", "true"),
+					resource.TestCheckResourceAttr(resName, "step.2.type", "kotlin_script"),
+					resource.TestCheckResourceAttr(resName, "step.2.code", "println(\"Hello World\")"),
+					resource.TestCheckResourceAttr(resName, "step.2.kotlin_path", ""),
+					testAccCheckStepProperties(&bc.ID, "build_image", map[string]string{
+						"docker.command.type":       "build",
+						"dockerfile.source":         "PATH",
+						"dockerfile.path":           "docker/Dockerfile",
+						"dockerfile.contextDir":     ".",
+						"docker.image.namesAndTags": "example/app:%build.number%",
+						"dockerImage.platform":      "linux",
+					}),
+					testAccCheckStepProperties(&bc.ID, "push_image", map[string]string{
+						"docker.command.type":      "push",
+						"docker.push.remove.image": "true",
+					}),
+					testAccCheckStepProperties(&bc.ID, "hello_kotlin", map[string]string{
+						"scriptType":    "customScript",
+						"scriptContent": "println(\"Hello World\")",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBuildConfig_StepsDockerUnsupportedCommand(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      TestAccBuildConfigStepsDockerUnsupportedCommand,
+				ExpectError: regexp.MustCompile("step.0: `command` must be one of"),
+			},
+		},
+	})
+}
+
+func TestAccBuildConfig_StepsGeneric(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_config.build_configuration_test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: TestAccBuildConfigStepsGeneric,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					resource.TestCheckResourceAttr(resName, "step.#", "1"),
+					resource.TestCheckResourceAttr(resName, "step.0.type", "generic"),
+					resource.TestCheckResourceAttr(resName, "step.0.runner_type", "Ant"),
+					resource.TestCheckResourceAttr(resName, "step.0.parameters.%", "2"),
+					resource.TestCheckResourceAttr(resName, "step.0.parameters.build-file-path", "build.xml"),
+					resource.TestCheckResourceAttr(resName, "step.0.parameters.target", "dist"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBuildConfig_StepsUpdateInPlace(t *testing.T) {
+	var bc api.BuildType
+	var buildID, testID string
+	resName := "teamcity_build_config.build_configuration_test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: TestAccBuildConfigStepsInPlace,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					testAccCheckResourceAttrValue(resName, "step.0.step_id", &buildID),
+					testAccCheckResourceAttrValue(resName, "step.1.step_id", &testID),
+					resource.TestCheckResourceAttr(resName, "step.0.enabled", "true"),
+					resource.TestCheckResourceAttr(resName, "step.1.enabled", "true"),
+				),
+			},
+			{
+				Config: TestAccBuildConfigStepsInPlaceUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "step.#", "2"),
+					resource.TestCheckResourceAttr(resName, "step.0.name", "test"),
+					resource.TestCheckResourceAttrPtr(resName, "step.0.step_id", &testID),
+					resource.TestCheckResourceAttr(resName, "step.0.enabled", "false"),
+					resource.TestCheckResourceAttr(resName, "step.1.name", "build"),
+					resource.TestCheckResourceAttrPtr(resName, "step.1.step_id", &buildID),
+					resource.TestCheckResourceAttr(resName, "step.1.code", "echo \"Building v2\""),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBuildConfig_StepsExecuteModeConditions(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_config.build_configuration_test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: TestAccBuildConfigStepsExecuteModeConditions,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					resource.TestCheckResourceAttr(resName, "step.0.execute_mode", "default"),
+					resource.TestCheckResourceAttr(resName, "step.0.condition.#", "0"),
+					resource.TestCheckResourceAttr(resName, "step.1.execute_mode", "execute_always"),
+					resource.TestCheckResourceAttr(resName, "step.1.condition.#", "2"),
+					resource.TestCheckResourceAttr(resName, "step.1.condition.0.type", "equals"),
+					resource.TestCheckResourceAttr(resName, "step.1.condition.0.name", "teamcity.build.branch.is_default"),
+					resource.TestCheckResourceAttr(resName, "step.1.condition.0.value", "true"),
+					resource.TestCheckResourceAttr(resName, "step.1.condition.1.type", "exists"),
+					resource.TestCheckResourceAttr(resName, "step.1.condition.1.name", "env.SLACK_WEBHOOK"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBuildConfig_FailureConditions(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_config.build_configuration_test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: TestAccBuildConfigFailureConditions,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					resource.TestCheckResourceAttr(resName, "failure_conditions.0.execution_timeout", "30"),
+					resource.TestCheckResourceAttr(resName, "failure_conditions.0.fail_on_exit_code", "false"),
+					resource.TestCheckResourceAttr(resName, "failure_conditions.0.fail_on_error_message", "true"),
+					resource.TestCheckResourceAttr(resName, "failure_conditions.0.fail_on_tests_failed", "true"),
+					resource.TestCheckResourceAttr(resName, "failure_conditions.0.fail_on_crash", "true"),
+				),
+			},
+			{
+				ResourceName:      resName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccBuildConfig_StepsUnsupportedAttribute(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      TestAccBuildConfigStepsUnsupportedAttribute,
+				ExpectError: regexp.MustCompile("step.0: `goals` isn't supported by gradle steps"),
+			},
+		},
+	})
+}
+
+func TestAccBuildConfig_Paused(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_config.build_configuration_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: TestAccBuildConfigPaused,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					resource.TestCheckResourceAttr(resName, "paused", "true"),
+					resource.TestCheckResourceAttr(resName, "paused_comment", "incident in progress"),
+				),
+			},
+			{
+				Config: TestAccBuildConfigBasic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					resource.TestCheckResourceAttr(resName, "paused", "false"),
+					resource.TestCheckResourceAttr(resName, "paused_comment", ""),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBuildConfig_PausedCommentWithoutPaused(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      TestAccBuildConfigPausedCommentWithoutPaused,
+				ExpectError: regexp.MustCompile("`paused_comment` can only be set when `paused` is true"),
+			},
+		},
+	})
+}
+
 func TestAccBuildConfig_Parameters(t *testing.T) {
 	var bc api.BuildType
 	resName := "teamcity_build_config.build_configuration_test"
@@ -522,6 +882,57 @@ func TestAccBuildConfig_UpdateParameters(t *testing.T) {
 	})
 }
 
+func TestAccBuildConfig_ParameterSpecs(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_config.build_configuration_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: TestAccBuildConfigParamSpecs,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					resource.TestCheckResourceAttr(resName, "env_params.%", "1"),
+					resource.TestCheckResourceAttr(resName, "param.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(resName, "param.*", map[string]string{
+						"name":  "env.API_KEY",
+						"value": "secret",
+						"type":  "password",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs(resName, "param.*", map[string]string{
+						"name":            "run_migrations",
+						"value":           "false",
+						"type":            "checkbox",
+						"display":         "prompt",
+						"checked_value":   "true",
+						"unchecked_value": "false",
+					}),
+				),
+			},
+			{
+				Config:   TestAccBuildConfigParamSpecs,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccBuildConfig_ParameterSpecsUnsupportedAttribute(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      TestAccBuildConfigParamSpecsUnsupportedAttribute,
+				ExpectError: regexp.MustCompile("`options` isn't supported by password parameters"),
+			},
+		},
+	})
+}
+
 func TestAccBuildConfig_Settings(t *testing.T) {
 	var bc api.BuildType
 	resName := "teamcity_build_config.build_configuration_test"
@@ -594,7 +1005,7 @@ func TestAccBuildConfig_SettingsUpdate(t *testing.T) {
 	})
 }
 
-func TestAccBuildConfig_VcsRoot(t *testing.T) {
+func TestAccBuildConfig_SettingsCheckout(t *testing.T) {
 	var bc api.BuildType
 	resName := "teamcity_build_config.build_configuration_test"
 	resource.Test(t, resource.TestCase{
@@ -603,15 +1014,205 @@ func TestAccBuildConfig_VcsRoot(t *testing.T) {
 		CheckDestroy: testAccCheckBuildConfigDestroy,
 		Steps: []resource.TestStep{
 			{
-				Config: TestAccBuildConfigVcsRoot,
+				Config: TestAccBuildConfigSettingsCheckout,
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckBuildConfigExists(resName, &bc),
-					testAccCheckVcsRootAttached(&bc.VcsRootEntries, "application", "+:*\\n-:README.MD"),
+					resource.TestCheckResourceAttr(resName, "settings.0.checkout_mode", "ON_SERVER"),
+					resource.TestCheckResourceAttr(resName, "settings.0.checkout_dir", "sources"),
+					resource.TestCheckResourceAttr(resName, "settings.0.clean_build", "true"),
+					resource.TestCheckResourceAttr(resName, "settings.0.show_dependency_changes", "true"),
 				),
 			},
-		},
-	})
-}
+			{
+				Config: TestAccBuildConfigSettingsCheckoutUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					resource.TestCheckResourceAttr(resName, "settings.0.checkout_mode", "MANUAL"),
+					resource.TestCheckResourceAttr(resName, "settings.0.checkout_dir", ""),
+					resource.TestCheckResourceAttr(resName, "settings.0.clean_build", "false"),
+					resource.TestCheckResourceAttr(resName, "settings.0.show_dependency_changes", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBuildConfig_VcsRoot(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_config.build_configuration_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: TestAccBuildConfigVcsRoot,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					testAccCheckVcsRootAttached(&bc.VcsRootEntries, "application", "+:*\\n-:README.MD"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBuildConfig_VcsRootUpdate(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_config.build_configuration_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: TestAccBuildConfigVcsRoot,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					resource.TestCheckResourceAttr(resName, "vcs_root.#", "1"),
+				),
+			},
+			{
+				Config: TestAccBuildConfigVcsRootUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					testAccCheckVcsRootAttached(&bc.VcsRootEntries, "application", "+:src"),
+					testAccCheckVcsRootAttached(&bc.VcsRootEntries, "tools", ""),
+					resource.TestCheckResourceAttr(resName, "vcs_root.#", "2"),
+				),
+			},
+			{
+				Config: TestAccBuildConfigVcsRootDetached,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					testAccCheckVcsRootAttached(&bc.VcsRootEntries, "tools", ""),
+					resource.TestCheckResourceAttr(resName, "vcs_root.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBuildConfig_TemplateInheritance(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_config.build_configuration_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: TestAccBuildConfigTemplateInheritance,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					resource.TestCheckResourceAttr(resName, "config_params.%", "1"),
+					resource.TestCheckResourceAttr(resName, "config_params.own", "value"),
+					resource.TestCheckResourceAttr(resName, "inherited_params.%", "1"),
+					resource.TestCheckResourceAttr(resName, "inherited_params.from_template", "template_value"),
+					resource.TestCheckResourceAttr(resName, "step.#", "1"),
+					resource.TestCheckResourceAttr(resName, "step.0.name", "own_step"),
+					resource.TestCheckResourceAttr(resName, "settings.0.build_number_format", "%build.counter%"),
+					resource.TestCheckResourceAttr(resName, "settings.0.concurrent_limit", "2"),
+				),
+			},
+			{
+				// values inherited from the template mustn't cause a diff
+				Config:   TestAccBuildConfigTemplateInheritance,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccBuildConfig_TemplateSettingsSurviveCreate(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_config.build_configuration_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: TestAccBuildConfigTemplateInheritance,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					// the settings which aren't set in the configuration are inherited from the template on the server
+					func(s *terraform.State) error {
+						if bc.Options.BuildNumberFormat != "1.0.%build.counter%" {
+							return fmt.Errorf("expected the build number format to be inherited from the template, got %q", bc.Options.BuildNumberFormat)
+						}
+						if !bc.Options.EnableStatusWidget {
+							return errors.New("expected the status widget to be inherited from the template")
+						}
+						if bc.Options.MaxSimultaneousBuilds != 2 {
+							return fmt.Errorf("expected the concurrent limit to be set, got %d", bc.Options.MaxSimultaneousBuilds)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccBuildConfig_TemplateOverrides(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_config.build_configuration_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: TestAccBuildConfigTemplateOverrides,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					resource.TestCheckResourceAttr(resName, "config_params.from_template", "overridden"),
+					resource.TestCheckResourceAttr(resName, "inherited_params.%", "0"),
+					resource.TestCheckResourceAttr(resName, "disabled_inherited.0.steps.#", "1"),
+					resource.TestCheckResourceAttrPair(resName, "disabled_inherited.0.steps.0", "teamcity_build_config.build_configuration_template", "step.0.step_id"),
+				),
+			},
+			{
+				Config: TestAccBuildConfigTemplateInheritance,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					resource.TestCheckResourceAttr(resName, "inherited_params.from_template", "template_value"),
+					resource.TestCheckResourceAttr(resName, "disabled_inherited.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBuildConfig_TemplateStepParams(t *testing.T) {
+	var bc api.BuildType
+	resName := "teamcity_build_config.build_configuration_test"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: TestAccBuildConfigTemplateStepParams,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					resource.TestCheckResourceAttr(resName, "inherited_step_params.#", "1"),
+					resource.TestCheckTypeSetElemAttrPair(resName, "inherited_step_params.*.step_id", "teamcity_build_config.build_configuration_template", "step.0.step_id"),
+					resource.TestCheckTypeSetElemNestedAttrs(resName, "inherited_step_params.*", map[string]string{
+						"params.script.content": "echo overridden",
+					}),
+				),
+			},
+			{
+				Config: TestAccBuildConfigTemplateInheritance,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBuildConfigExists(resName, &bc),
+					resource.TestCheckResourceAttr(resName, "inherited_step_params.#", "0"),
+				),
+			},
+		},
+	})
+}
 
 func TestAccBuildConfig_AttachTemplates(t *testing.T) {
 	var bc, t1, t2 api.BuildType
@@ -651,7 +1252,7 @@ func TestAccBuildConfig_AttachTemplates(t *testing.T) {
 
 func testAccCheckStepRemoved(buildTypeID *string, stepRemoved map[string]string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 		exists, _ := testStepExists(client, *buildTypeID, stepRemoved)
 		if exists {
 			return fmt.Errorf("expected step %s to be removed, but still exists", stepRemoved["name"])
@@ -660,14 +1261,80 @@ func testAccCheckStepRemoved(buildTypeID *string, stepRemoved map[string]string)
 	}
 }
 
+// testAccCheckResourceAttrValue stores the value of an attribute, so later steps can check it didn't change
+func testAccCheckResourceAttrValue(n string, key string, value *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("not found: %s", n)
+		}
+		v, ok := rs.Primary.Attributes[key]
+		if !ok {
+			return fmt.Errorf("%s: attribute '%s' not found", n, key)
+		}
+		*value = v
+		return nil
+	}
+}
+
 func testAccCheckStepExists(buildTypeID *string, stepExpected map[string]string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 		_, err := testStepExists(client, *buildTypeID, stepExpected)
 		return err
 	}
 }
 
+// testAccCheckStepProperties checks the properties of a step as stored by TeamCity, which go-teamcity can't read for
+// most runners
+func testAccCheckStepProperties(buildTypeID *string, stepName string, expected map[string]string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		url := fmt.Sprintf("%s/app/rest/buildTypes/id:%s/steps", strings.TrimSuffix(os.Getenv("TEAMCITY_ADDR"), "/"), *buildTypeID)
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Accept", "application/json")
+		if token := os.Getenv("TEAMCITY_TOKEN"); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		} else {
+			req.SetBasicAuth(os.Getenv("TEAMCITY_USER"), os.Getenv("TEAMCITY_PASSWORD"))
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("error when checking steps: %s", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("error when checking steps: %s", resp.Status)
+		}
+
+		var steps struct {
+			Step []struct {
+				Name       string          `json:"name"`
+				Properties *api.Properties `json:"properties"`
+			} `json:"step"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&steps); err != nil {
+			return fmt.Errorf("error when checking steps: %s", err)
+		}
+
+		for _, step := range steps.Step {
+			if step.Name != stepName {
+				continue
+			}
+			for name, value := range expected {
+				if actual, _ := step.Properties.GetOk(name); actual != value {
+					return fmt.Errorf("property '%s' of step '%s' differs, actual: %s, expected: %s", name, stepName, actual, value)
+				}
+			}
+			return nil
+		}
+		return fmt.Errorf("the step named '%s' was not found", stepName)
+	}
+}
+
 func testStepExists(client *api.Client, buildTypeID string, stepExpected map[string]string) (bool, error) {
 	steps, err := client.BuildTypes.GetSteps(buildTypeID)
 	if err != nil {
@@ -757,13 +1424,13 @@ func testAccCheckVcsRootAttached(vcs *[]*api.VcsRootEntry, n string, co string)
 
 func testAccCheckBuildConfigExists(n string, out *api.BuildType) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 		return buildConfigExistsHelper(n, s, client, out)
 	}
 }
 
 func updateBuildCounter(buildType *api.BuildType, counter int) {
-	client := testAccProvider.Meta().(*api.Client)
+	client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 	id := buildType.ID
 
 	bt, err := client.BuildTypes.GetByID(id)
@@ -800,7 +1467,7 @@ func buildConfigExistsHelper(n string, s *terraform.State, client *api.Client, o
 }
 
 func testAccCheckBuildConfigDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*api.Client)
+	client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 	return buildConfigDestroyHelper(s, client)
 }
 
@@ -864,6 +1531,56 @@ resource "teamcity_build_config" "build_configuration_test" {
 }
 `
 
+const TestAccBuildConfigExternalID = `
+resource "teamcity_project" "build_config_project_test" {
+  name = "build_config_project_test"
+}
+
+resource "teamcity_build_config" "build_configuration_test" {
+	name = "build config test"
+	external_id = "%s"
+	project_id = teamcity_project.build_config_project_test.id
+
+	step {
+		type = "cmd_line"
+		name = "build"
+		code = "echo build"
+	}
+}
+`
+
+const TestAccBuildConfigMoveProject = `
+resource "teamcity_project" "parent" {
+  name = "build_config_project_test"
+}
+
+resource "teamcity_project" "source" {
+  name = "source"
+  parent_id = teamcity_project.parent.id
+}
+
+resource "teamcity_project" "target" {
+  name = "target"
+  parent_id = teamcity_project.parent.id
+}
+
+resource "teamcity_vcs_root_git" "build_config_vcsroot_test" {
+	name = "application"
+	project_id = teamcity_project.parent.id
+	fetch_url = "https://github.com/kelseyhightower/nocode"
+	default_branch = "refs/head/master"
+}
+
+resource "teamcity_build_config" "build_configuration_test" {
+	name = "build config test"
+	project_id = teamcity_project.%s.id
+
+	vcs_root {
+		id = teamcity_vcs_root_git.build_config_vcsroot_test.id
+	}
+}
+`
+
 const TestAccBuildConfigBasicUpdated = `
 resource "teamcity_project" "build_config_project_test" {
   name = "build_config_project_test"
@@ -907,6 +1624,34 @@ resource "teamcity_project" "project" {
 	  }
   }
 `
+const TestAccBuildConfigPaused = `
+resource "teamcity_project" "build_config_project_test" {
+  name = "build_config_project_test"
+}
+
+resource "teamcity_build_config" "build_configuration_test" {
+	name = "build config test"
+	project_id = "${teamcity_project.build_config_project_test.id}"
+	description = "build config test desc"
+
+	paused = true
+	paused_comment = "incident in progress"
+}
+`
+
+const TestAccBuildConfigPausedCommentWithoutPaused = `
+resource "teamcity_project" "build_config_project_test" {
+  name = "build_config_project_test"
+}
+
+resource "teamcity_build_config" "build_configuration_test" {
+	name = "build config test"
+	project_id = "${teamcity_project.build_config_project_test.id}"
+
+	paused_comment = "incident in progress"
+}
+`
+
 const TestAccBuildConfigParams = `
 resource "teamcity_project" "build_config_project_test" {
   name = "build_config_project_test"
@@ -951,6 +1696,53 @@ resource "teamcity_build_config" "build_configuration_test" {
 }
 `
 
+const TestAccBuildConfigParamSpecs = `
+resource "teamcity_project" "build_config_project_test" {
+  name = "build_config_project_test"
+}
+
+resource "teamcity_build_config" "build_configuration_test" {
+	name = "build config test"
+	project_id = "${teamcity_project.build_config_project_test.id}"
+
+	env_params = {
+		DEPLOY_SERVER = "server.com"
+	}
+
+	param {
+		name  = "env.API_KEY"
+		value = "secret"
+		type  = "password"
+	}
+
+	param {
+		name            = "run_migrations"
+		value           = "false"
+		type            = "checkbox"
+		display         = "prompt"
+		checked_value   = "true"
+		unchecked_value = "false"
+	}
+}
+`
+
+const TestAccBuildConfigParamSpecsUnsupportedAttribute = `
+resource "teamcity_project" "build_config_project_test" {
+  name = "build_config_project_test"
+}
+
+resource "teamcity_build_config" "build_configuration_test" {
+	name = "build config test"
+	project_id = "${teamcity_project.build_config_project_test.id}"
+
+	param {
+		name    = "env.API_KEY"
+		type    = "password"
+		options = ["a", "b"]
+	}
+}
+`
+
 const TestAccBuildConfigSettings = `
 resource "teamcity_project" "build_config_project_test" {
   name = "build_config_project_test"
@@ -995,20 +1787,53 @@ resource "teamcity_build_config" "build_configuration_test" {
 }
 `
 
-const TestAccBuildConfigVcsRoot = `
+const TestAccBuildConfigSettingsCheckout = `
 resource "teamcity_project" "build_config_project_test" {
   name = "build_config_project_test"
 }
 
-resource "teamcity_vcs_root_git" "build_config_vcsroot_test" {
-	name = "application"
-	project_id = "${teamcity_project.build_config_project_test.id}"
-	fetch_url = "https://github.com/kelseyhightower/nocode"
-	default_branch = "refs/head/master"
-}
-
 resource "teamcity_build_config" "build_configuration_test" {
-	name = "build config test"
+  name = "build config test"
+  project_id = "${teamcity_project.build_config_project_test.id}"
+
+  settings {
+    checkout_mode = "ON_SERVER"
+    checkout_dir = "sources"
+    clean_build = true
+    show_dependency_changes = true
+  }
+}
+`
+
+const TestAccBuildConfigSettingsCheckoutUpdated = `
+resource "teamcity_project" "build_config_project_test" {
+  name = "build_config_project_test"
+}
+
+resource "teamcity_build_config" "build_configuration_test" {
+  name = "build config test"
+  project_id = "${teamcity_project.build_config_project_test.id}"
+
+  settings {
+    checkout_mode = "MANUAL"
+  }
+}
+`
+
+const TestAccBuildConfigVcsRoot = `
+resource "teamcity_project" "build_config_project_test" {
+  name = "build_config_project_test"
+}
+
+resource "teamcity_vcs_root_git" "build_config_vcsroot_test" {
+	name = "application"
+	project_id = "${teamcity_project.build_config_project_test.id}"
+	fetch_url = "https://github.com/kelseyhightower/nocode"
+	default_branch = "refs/head/master"
+}
+
+resource "teamcity_build_config" "build_configuration_test" {
+	name = "build config test"
 	project_id = "${teamcity_project.build_config_project_test.id}"
 
 	vcs_root {
@@ -1018,6 +1843,199 @@ resource "teamcity_build_config" "build_configuration_test" {
 }
 `
 
+const TestAccBuildConfigVcsRootUpdated = `
+resource "teamcity_project" "build_config_project_test" {
+  name = "build_config_project_test"
+}
+
+resource "teamcity_vcs_root_git" "build_config_vcsroot_test" {
+	name = "application"
+	project_id = "${teamcity_project.build_config_project_test.id}"
+	fetch_url = "https://github.com/kelseyhightower/nocode"
+	default_branch = "refs/head/master"
+}
+
+resource "teamcity_vcs_root_git" "build_config_vcsroot_tools" {
+	name = "tools"
+	project_id = "${teamcity_project.build_config_project_test.id}"
+	fetch_url = "https://github.com/kelseyhightower/nocode"
+	default_branch = "refs/head/master"
+}
+
+resource "teamcity_build_config" "build_configuration_test" {
+	name = "build config test"
+	project_id = "${teamcity_project.build_config_project_test.id}"
+
+	vcs_root {
+		id = "${teamcity_vcs_root_git.build_config_vcsroot_test.id}"
+		checkout_rules = ["+:src"]
+	}
+
+	vcs_root {
+		id = "${teamcity_vcs_root_git.build_config_vcsroot_tools.id}"
+	}
+}
+`
+
+const TestAccBuildConfigVcsRootDetached = `
+resource "teamcity_project" "build_config_project_test" {
+  name = "build_config_project_test"
+}
+
+resource "teamcity_vcs_root_git" "build_config_vcsroot_test" {
+	name = "application"
+	project_id = "${teamcity_project.build_config_project_test.id}"
+	fetch_url = "https://github.com/kelseyhightower/nocode"
+	default_branch = "refs/head/master"
+}
+
+resource "teamcity_vcs_root_git" "build_config_vcsroot_tools" {
+	name = "tools"
+	project_id = "${teamcity_project.build_config_project_test.id}"
+	fetch_url = "https://github.com/kelseyhightower/nocode"
+	default_branch = "refs/head/master"
+}
+
+resource "teamcity_build_config" "build_configuration_test" {
+	name = "build config test"
+	project_id = "${teamcity_project.build_config_project_test.id}"
+
+	vcs_root {
+		id = "${teamcity_vcs_root_git.build_config_vcsroot_tools.id}"
+	}
+}
+`
+
+const TestAccBuildConfigTemplateInheritance = `
+resource "teamcity_project" "build_config_project_test" {
+  name = "build_config_project_test"
+}
+
+resource "teamcity_build_config" "build_configuration_template" {
+	name = "build template"
+	is_template = true
+	project_id = teamcity_project.build_config_project_test.id
+
+	config_params = {
+		from_template = "template_value"
+	}
+
+	settings {
+		build_number_format = "1.0.%build.counter%"
+		status_widget = true
+	}
+
+	step {
+		type = "cmd_line"
+		name = "template_step"
+		code = "echo template"
+	}
+}
+
+resource "teamcity_build_config" "build_configuration_test" {
+	name = "build config test"
+	project_id = teamcity_project.build_config_project_test.id
+	templates = [ teamcity_build_config.build_configuration_template.id ]
+
+	config_params = {
+		own = "value"
+	}
+
+	settings {
+		concurrent_limit = 2
+	}
+
+	step {
+		type = "cmd_line"
+		name = "own_step"
+		code = "echo own"
+	}
+}
+`
+
+const TestAccBuildConfigTemplateStepParams = `
+resource "teamcity_project" "build_config_project_test" {
+  name = "build_config_project_test"
+}
+
+resource "teamcity_build_config" "build_configuration_template" {
+	name = "build template"
+	is_template = true
+	project_id = teamcity_project.build_config_project_test.id
+
+	step {
+		type = "cmd_line"
+		name = "template_step"
+		code = "echo template"
+	}
+}
+
+resource "teamcity_build_config" "build_configuration_test" {
+	name = "build config test"
+	project_id = teamcity_project.build_config_project_test.id
+	templates = [ teamcity_build_config.build_configuration_template.id ]
+
+	inherited_step_params {
+		step_id = teamcity_build_config.build_configuration_template.step.0.step_id
+		params = {
+			"script.content" = "echo overridden"
+		}
+	}
+}
+`
+
+const TestAccBuildConfigTemplateOverrides = `
+resource "teamcity_project" "build_config_project_test" {
+  name = "build_config_project_test"
+}
+
+resource "teamcity_build_config" "build_configuration_template" {
+	name = "build template"
+	is_template = true
+	project_id = teamcity_project.build_config_project_test.id
+
+	config_params = {
+		from_template = "template_value"
+	}
+
+	settings {
+		build_number_format = "1.0.%build.counter%"
+		status_widget = true
+	}
+
+	step {
+		type = "cmd_line"
+		name = "template_step"
+		code = "echo template"
+	}
+}
+
+resource "teamcity_build_config" "build_configuration_test" {
+	name = "build config test"
+	project_id = teamcity_project.build_config_project_test.id
+	templates = [ teamcity_build_config.build_configuration_template.id ]
+
+	config_params = {
+		own = "value"
+		from_template = "overridden"
+	}
+
+	settings {
+		concurrent_limit = 2
+	}
+
+	step {
+		type = "cmd_line"
+		name = "own_step"
+		code = "echo own"
+	}
+
+	disabled_inherited {
+		steps = [ teamcity_build_config.build_configuration_template.step[0].step_id ]
+	}
+}
+`
+
 const TestAccBuildConfigAttachTemplates = `
 resource "teamcity_project" "build_config_project_test" {
   name = "build_config_project_test"
@@ -1170,6 +2188,254 @@ resource "teamcity_build_config" "build_configuration_test" {
 }
 `
 
+const TestAccBuildConfigStepsGradleMavenDotnet = `
+resource "teamcity_project" "build_config_project_test" {
+  name = "build_config_project_test"
+}
+
+resource "teamcity_build_config" "build_configuration_test" {
+	name = "build config test"
+	project_id = "${teamcity_project.build_config_project_test.id}"
+
+	step {
+		type = "gradle"
+		name = "build_gradle"
+		tasks = "clean build"
+		use_wrapper = true
+	}
+
+	step {
+		type = "maven"
+		name = "build_maven"
+		goals = "clean package"
+		build_file = "service/pom.xml"
+	}
+
+	step {
+		type = "dotnet"
+		name = "test_dotnet"
+		command = "test"
+		projects = "src/App.sln"
+		configuration = "Release"
+	}
+}
+`
+
+const TestAccBuildConfigStepsGradleMavenDotnetUpdated = `
+resource "teamcity_project" "build_config_project_test" {
+  name = "build_config_project_test"
+}
+
+resource "teamcity_build_config" "build_configuration_test" {
+	name = "build config test"
+	project_id = "${teamcity_project.build_config_project_test.id}"
+
+	step {
+		type = "gradle"
+		name = "build_gradle"
+		tasks = "clean check"
+		jdk_home = "%env.JDK_11_0%"
+	}
+
+	step {
+		type = "dotnet"
+		name = "test_dotnet"
+		command = "test"
+		projects = "src/App.sln"
+		configuration = "Release"
+	}
+}
+`
+
+const TestAccBuildConfigStepsDockerKotlinScript = `
+resource "teamcity_project" "build_config_project_test" {
+  name = "build_config_project_test"
+}
+
+resource "teamcity_build_config" "build_configuration_test" {
+	name = "build config test"
+	project_id = "${teamcity_project.build_config_project_test.id}"
+
+	step {
+		type = "docker"
+		name = "build_image"
+		command = "build"
+		file = "docker/Dockerfile"
+		context_dir = "."
+		image_names = "example/app:%build.number%"
+		platform = "linux"
+	}
+
+	step {
+		type = "docker"
+		name = "push_image"
+		command = "push"
+		image_names = "example/app:%build.number%"
+		remove_image = true
+	}
+
+	step {
+		type = "kotlin_script"
+		name = "hello_kotlin"
+		code = "println(\"Hello World\")"
+	}
+}
+`
+
+const TestAccBuildConfigStepsDockerUnsupportedCommand = `
+resource "teamcity_project" "build_config_project_test" {
+  name = "build_config_project_test"
+}
+
+resource "teamcity_build_config" "build_configuration_test" {
+	name = "build config test"
+	project_id = "${teamcity_project.build_config_project_test.id}"
+
+	step {
+		type = "docker"
+		name = "test_image"
+		command = "test"
+	}
+}
+`
+
+const TestAccBuildConfigStepsGeneric = `
+resource "teamcity_project" "build_config_project_test" {
+  name = "build_config_project_test"
+}
+
+resource "teamcity_build_config" "build_configuration_test" {
+	name = "build config test"
+	project_id = "${teamcity_project.build_config_project_test.id}"
+
+	step {
+		type = "generic"
+		name = "build_ant"
+		runner_type = "Ant"
+		parameters = {
+			"build-file-path" = "build.xml"
+			"target"          = "dist"
+		}
+	}
+}
+`
+
+const TestAccBuildConfigStepsInPlace = `
+resource "teamcity_project" "build_config_project_test" {
+  name = "build_config_project_test"
+}
+
+resource "teamcity_build_config" "build_configuration_test" {
+	name = "build config test"
+	project_id = "${teamcity_project.build_config_project_test.id}"
+
+	step {
+		type = "cmd_line"
+		name = "build"
+		code = "echo \"Building\""
+	}
+
+	step {
+		type = "cmd_line"
+		name = "test"
+		code = "echo \"Testing\""
+	}
+}
+`
+
+const TestAccBuildConfigStepsInPlaceUpdated = `
+resource "teamcity_project" "build_config_project_test" {
+  name = "build_config_project_test"
+}
+
+resource "teamcity_build_config" "build_configuration_test" {
+	name = "build config test"
+	project_id = "${teamcity_project.build_config_project_test.id}"
+
+	step {
+		type = "cmd_line"
+		name = "test"
+		code = "echo \"Testing\""
+		enabled = false
+	}
+
+	step {
+		type = "cmd_line"
+		name = "build"
+		code = "echo \"Building v2\""
+	}
+}
+`
+
+const TestAccBuildConfigStepsExecuteModeConditions = `
+resource "teamcity_project" "build_config_project_test" {
+  name = "build_config_project_test"
+}
+
+resource "teamcity_build_config" "build_configuration_test" {
+	name = "build config test"
+	project_id = "${teamcity_project.build_config_project_test.id}"
+
+	step {
+		type = "cmd_line"
+		name = "build"
+		code = "echo \"Building\""
+	}
+
+	step {
+		type = "cmd_line"
+		name = "notify"
+		code = "./notify.sh"
+		execute_mode = "execute_always"
+
+		condition {
+			type = "equals"
+			name = "teamcity.build.branch.is_default"
+			value = "true"
+		}
+
+		condition {
+			type = "exists"
+			name = "env.SLACK_WEBHOOK"
+		}
+	}
+}
+`
+
+const TestAccBuildConfigFailureConditions = `
+resource "teamcity_project" "build_config_project_test" {
+  name = "build_config_project_test"
+}
+
+resource "teamcity_build_config" "build_configuration_test" {
+	name = "build config test"
+	project_id = "${teamcity_project.build_config_project_test.id}"
+
+	failure_conditions {
+		execution_timeout = 30
+		fail_on_exit_code = false
+		fail_on_error_message = true
+	}
+}
+`
+
+const TestAccBuildConfigStepsUnsupportedAttribute = `
+resource "teamcity_project" "build_config_project_test" {
+  name = "build_config_project_test"
+}
+
+resource "teamcity_build_config" "build_configuration_test" {
+	name = "build config test"
+	project_id = "${teamcity_project.build_config_project_test.id}"
+
+	step {
+		type = "gradle"
+		name = "build_gradle"
+		goals = "clean package"
+	}
+}
+`
+
 const TestAccBuildConfigurationIdWithParent = `
 resource "teamcity_project" "parent" {
 	name = "parent"
@@ -0,0 +1,213 @@
+package teamcity
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// failureOnMetricUnits maps the values of `units` to the units of the build feature
+var failureOnMetricUnits = map[string]string{
+	"default": "metricUnitsDefault",
+	"percent": "metricUnitsPercent",
+}
+
+// failureOnMetricAnchors maps the values of `compare_to` to the builds the metric is compared with. A metric compared
+// to a value doesn't use a build.
+var failureOnMetricAnchors = map[string]string{
+	"value":           "",
+	"last_successful": "lastSuccessful",
+	"last_pinned":     "lastPinned",
+	"last_finished":   "lastFinished",
+	"build_tag":       "buildTag",
+}
+
+func resourceBuildFailureConditionMetric() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceBuildFailureConditionMetricCreate,
+		ReadContext:   resourceBuildFailureConditionMetricRead,
+		DeleteContext: resourceBuildFailureConditionMetricDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		CustomizeDiff: func(_ context.Context, diff *schema.ResourceDiff, _ interface{}) error {
+			if diff.Get("compare_to").(string) == "build_tag" && diff.Get("build_tag").(string) == "" {
+				return fmt.Errorf("`build_tag` is required when `compare_to` is \"build_tag\"")
+			}
+			return nil
+		},
+
+		Schema: map[string]*schema.Schema{
+			"build_config_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"metric": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"comparison": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"more", "less"}, false),
+			},
+			"threshold": {
+				Type:     schema.TypeFloat,
+				Required: true,
+				ForceNew: true,
+			},
+			"units": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "default",
+				ValidateFunc: validation.StringInSlice([]string{"default", "percent"}, false),
+			},
+			"compare_to": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "last_successful",
+				ValidateFunc: validation.StringInSlice([]string{"value", "last_successful", "last_pinned", "last_finished", "build_tag"}, false),
+			},
+			"build_tag": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"stop_build": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  false,
+			},
+		},
+	}
+}
+
+func resourceBuildFailureConditionMetricCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	unlock, err := meta.(*Client).lockBuildType(ctx, d.Get("build_config_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
+
+	buildConfigID := d.Get("build_config_id").(string)
+
+	// validates the Build Configuration exists
+	if diags := validateBuildConfigExists(ctx, meta.(*Client), "build_config_id", buildConfigID); diags.HasError() {
+		return diags
+	}
+
+	props := api.NewPropertiesEmpty()
+	props.AddOrReplaceValue("metricKey", d.Get("metric").(string))
+	props.AddOrReplaceValue("moreOrLess", d.Get("comparison").(string))
+	props.AddOrReplaceValue("metricThreshold", strconv.FormatFloat(d.Get("threshold").(float64), 'f', -1, 64))
+	props.AddOrReplaceValue("metricUnits", failureOnMetricUnits[d.Get("units").(string)])
+	props.AddOrReplaceValue("stopBuildOnFailure", strconv.FormatBool(d.Get("stop_build").(bool)))
+	if anchor := failureOnMetricAnchors[d.Get("compare_to").(string)]; anchor != "" {
+		props.AddOrReplaceValue("withBuildAnchor", "true")
+		props.AddOrReplaceValue("anchorBuild", anchor)
+		if v, ok := d.GetOk("build_tag"); ok {
+			props.AddOrReplaceValue("buildTag", v.(string))
+		}
+	} else {
+		props.AddOrReplaceValue("withBuildAnchor", "false")
+	}
+
+	created, err := addBuildFeature(meta.(*Client).rest(ctx), buildConfigID, &buildFeature{
+		Type:       featureTypeFailureOnMetric,
+		Properties: props,
+	})
+	if err != nil {
+		return diag.FromErr(describeAPIError(err, "Build Failure Condition"))
+	}
+
+	d.SetId(fmt.Sprintf("%s|%s", buildConfigID, created.ID))
+
+	return resourceBuildFailureConditionMetricRead(ctx, d, meta)
+}
+
+func resourceBuildFailureConditionMetricRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id, err := ParseBuildFailureConditionID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	dt, err := getBuildFeature(meta.(*Client).rest(ctx), id.BuildConfigID, id.ConditionID)
+	if err != nil {
+		// handles this being deleted outside of TF
+		if isNotFoundError(err) {
+			log.Printf("[DEBUG] Build Failure Condition was not found - removing from state!")
+			d.SetId("")
+			return nil
+		}
+
+		return diag.FromErr(describeAPIError(err, "Build Failure Condition"))
+	}
+	if dt.Type != featureTypeFailureOnMetric {
+		return diag.Errorf("build feature '%s' of Build Configuration '%s' is a '%s', not a failure condition on a metric change", id.ConditionID, id.BuildConfigID, dt.Type)
+	}
+
+	props := propertiesMap(dt.Properties)
+	threshold, err := strconv.ParseFloat(props["metricThreshold"], 64)
+	if err != nil {
+		return diag.Errorf("invalid threshold '%s' of Build Failure Condition '%s': %s", props["metricThreshold"], d.Id(), err)
+	}
+	units := "default"
+	for k, v := range failureOnMetricUnits {
+		if v == props["metricUnits"] {
+			units = k
+		}
+	}
+	compareTo := "value"
+	if props["withBuildAnchor"] == "true" {
+		for k, v := range failureOnMetricAnchors {
+			if v != "" && v == props["anchorBuild"] {
+				compareTo = k
+			}
+		}
+	}
+
+	d.Set("build_config_id", id.BuildConfigID)
+	d.Set("metric", props["metricKey"])
+	d.Set("comparison", props["moreOrLess"])
+	d.Set("threshold", threshold)
+	d.Set("units", units)
+	d.Set("compare_to", compareTo)
+	d.Set("build_tag", props["buildTag"])
+	d.Set("stop_build", props["stopBuildOnFailure"] == "true")
+
+	return nil
+}
+
+func resourceBuildFailureConditionMetricDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	unlock, err := meta.(*Client).lockBuildType(ctx, d.Get("build_config_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
+
+	id, err := ParseBuildFailureConditionID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := deleteBuildFeature(meta.(*Client).rest(ctx), id.BuildConfigID, id.ConditionID); err != nil {
+		if !isNotFoundError(err) {
+			return diag.FromErr(describeAPIError(err, "Build Failure Condition"))
+		}
+	}
+
+	return nil
+}
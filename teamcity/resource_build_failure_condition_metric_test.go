@@ -0,0 +1,87 @@
+package teamcity_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccTeamcityBuildFailureConditionMetric_Basic(t *testing.T) {
+	resName := "teamcity_build_failure_condition_metric.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildFailureConditionDestroy("teamcity_build_failure_condition_metric"),
+		Steps: []resource.TestStep{
+			{
+				Config: TestAccBuildFailureConditionMetric_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "metric", "testCount"),
+					resource.TestCheckResourceAttr(resName, "comparison", "less"),
+					resource.TestCheckResourceAttr(resName, "threshold", "10"),
+					resource.TestCheckResourceAttr(resName, "units", "percent"),
+					resource.TestCheckResourceAttr(resName, "compare_to", "last_successful"),
+				),
+			},
+			{
+				ResourceName:      resName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccTeamcityBuildFailureConditionMetric_BuildTagRequired(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      TestAccBuildFailureConditionMetric_buildTagMissing,
+				ExpectError: regexp.MustCompile("`build_tag` is required"),
+			},
+		},
+	})
+}
+
+const TestAccBuildFailureConditionMetric_basic = `
+resource "teamcity_project" "test" {
+  name = "Build Failure Condition"
+}
+
+resource "teamcity_build_config" "test" {
+  name = "BuildConfig"
+  project_id = teamcity_project.test.id
+}
+
+resource "teamcity_build_failure_condition_metric" "test" {
+  build_config_id = teamcity_build_config.test.id
+  metric = "testCount"
+  comparison = "less"
+  threshold = 10
+  units = "percent"
+}
+`
+
+const TestAccBuildFailureConditionMetric_buildTagMissing = `
+resource "teamcity_project" "test" {
+  name = "Build Failure Condition"
+}
+
+resource "teamcity_build_config" "test" {
+  name = "BuildConfig"
+  project_id = teamcity_project.test.id
+}
+
+resource "teamcity_build_failure_condition_metric" "test" {
+  build_config_id = teamcity_build_config.test.id
+  metric = "buildDuration"
+  comparison = "more"
+  threshold = 20
+  units = "percent"
+  compare_to = "build_tag"
+}
+`
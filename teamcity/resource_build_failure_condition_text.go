@@ -0,0 +1,163 @@
+package teamcity
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// failureOnMessageMatches maps the values of `match` to the condition types of the build feature
+var failureOnMessageMatches = map[string]string{
+	"contains": "contains",
+	"regex":    "matchesRegex",
+}
+
+func resourceBuildFailureConditionText() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceBuildFailureConditionTextCreate,
+		ReadContext:   resourceBuildFailureConditionTextRead,
+		DeleteContext: resourceBuildFailureConditionTextDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"build_config_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"pattern": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"match": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "contains",
+				ValidateFunc: validation.StringInSlice([]string{"contains", "regex"}, false),
+			},
+			"fail_if_missing": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  false,
+			},
+			"failure_message": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"stop_build": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  false,
+			},
+		},
+	}
+}
+
+func resourceBuildFailureConditionTextCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	unlock, err := meta.(*Client).lockBuildType(ctx, d.Get("build_config_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
+
+	buildConfigID := d.Get("build_config_id").(string)
+
+	// validates the Build Configuration exists
+	if diags := validateBuildConfigExists(ctx, meta.(*Client), "build_config_id", buildConfigID); diags.HasError() {
+		return diags
+	}
+
+	props := api.NewPropertiesEmpty()
+	props.AddOrReplaceValue("buildFailureOnMessage.messagePattern", d.Get("pattern").(string))
+	props.AddOrReplaceValue("buildFailureOnMessage.conditionType", failureOnMessageMatches[d.Get("match").(string)])
+	props.AddOrReplaceValue("buildFailureOnMessage.reverse", strconv.FormatBool(d.Get("fail_if_missing").(bool)))
+	props.AddOrReplaceValue("buildFailureOnMessage.stopBuildOnFailure", strconv.FormatBool(d.Get("stop_build").(bool)))
+	if v, ok := d.GetOk("failure_message"); ok {
+		props.AddOrReplaceValue("buildFailureOnMessage.outputText", v.(string))
+	}
+
+	created, err := addBuildFeature(meta.(*Client).rest(ctx), buildConfigID, &buildFeature{
+		Type:       featureTypeFailureOnMessage,
+		Properties: props,
+	})
+	if err != nil {
+		return diag.FromErr(describeAPIError(err, "Build Failure Condition"))
+	}
+
+	d.SetId(fmt.Sprintf("%s|%s", buildConfigID, created.ID))
+
+	return resourceBuildFailureConditionTextRead(ctx, d, meta)
+}
+
+func resourceBuildFailureConditionTextRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id, err := ParseBuildFailureConditionID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	dt, err := getBuildFeature(meta.(*Client).rest(ctx), id.BuildConfigID, id.ConditionID)
+	if err != nil {
+		// handles this being deleted outside of TF
+		if isNotFoundError(err) {
+			log.Printf("[DEBUG] Build Failure Condition was not found - removing from state!")
+			d.SetId("")
+			return nil
+		}
+
+		return diag.FromErr(describeAPIError(err, "Build Failure Condition"))
+	}
+	if dt.Type != featureTypeFailureOnMessage {
+		return diag.Errorf("build feature '%s' of Build Configuration '%s' is a '%s', not a failure condition on text in the build log", id.ConditionID, id.BuildConfigID, dt.Type)
+	}
+
+	props := propertiesMap(dt.Properties)
+	match := "contains"
+	for k, v := range failureOnMessageMatches {
+		if v == props["buildFailureOnMessage.conditionType"] {
+			match = k
+		}
+	}
+
+	d.Set("build_config_id", id.BuildConfigID)
+	d.Set("pattern", props["buildFailureOnMessage.messagePattern"])
+	d.Set("match", match)
+	d.Set("fail_if_missing", props["buildFailureOnMessage.reverse"] == "true")
+	d.Set("failure_message", props["buildFailureOnMessage.outputText"])
+	d.Set("stop_build", props["buildFailureOnMessage.stopBuildOnFailure"] == "true")
+
+	return nil
+}
+
+func resourceBuildFailureConditionTextDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	unlock, err := meta.(*Client).lockBuildType(ctx, d.Get("build_config_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
+
+	id, err := ParseBuildFailureConditionID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := deleteBuildFeature(meta.(*Client).rest(ctx), id.BuildConfigID, id.ConditionID); err != nil {
+		if !isNotFoundError(err) {
+			return diag.FromErr(describeAPIError(err, "Build Failure Condition"))
+		}
+	}
+
+	return nil
+}
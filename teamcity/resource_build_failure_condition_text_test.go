@@ -0,0 +1,82 @@
+package teamcity_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cvbarros/terraform-provider-teamcity/teamcity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccTeamcityBuildFailureConditionText_Basic(t *testing.T) {
+	resName := "teamcity_build_failure_condition_text.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBuildFailureConditionDestroy("teamcity_build_failure_condition_text"),
+		Steps: []resource.TestStep{
+			{
+				Config: TestAccBuildFailureConditionText_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "pattern", "OutOfMemoryError"),
+					resource.TestCheckResourceAttr(resName, "match", "contains"),
+					resource.TestCheckResourceAttr(resName, "fail_if_missing", "false"),
+					resource.TestCheckResourceAttr(resName, "failure_message", "The build ran out of memory"),
+					resource.TestCheckResourceAttr(resName, "stop_build", "true"),
+				),
+			},
+			{
+				ResourceName:      resName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckBuildFailureConditionDestroy(resourceType string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != resourceType {
+				continue
+			}
+
+			id, err := teamcity.ParseBuildFailureConditionID(rs.Primary.ID)
+			if err != nil {
+				return err
+			}
+
+			// go-teamcity can't read failure conditions, but reports when they don't exist
+			srv := client.BuildFeatureService(id.BuildConfigID)
+			if _, err := srv.GetByID(id.ConditionID); err != nil && strings.Contains(err.Error(), "404") {
+				continue
+			}
+
+			return fmt.Errorf("Build Failure Condition still exists")
+		}
+		return nil
+	}
+}
+
+const TestAccBuildFailureConditionText_basic = `
+resource "teamcity_project" "test" {
+  name = "Build Failure Condition"
+}
+
+resource "teamcity_build_config" "test" {
+  name = "BuildConfig"
+  project_id = teamcity_project.test.id
+}
+
+resource "teamcity_build_failure_condition_text" "test" {
+  build_config_id = teamcity_build_config.test.id
+  pattern = "OutOfMemoryError"
+  failure_message = "The build ran out of memory"
+  stop_build = true
+}
+`
@@ -1,18 +1,19 @@
 package teamcity
 
 import (
-	"fmt"
+	"context"
 	"log"
 
 	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceBuildTriggerBuildFinish() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceBuildTriggerBuildFinishCreate,
-		Read:   resourceBuildTriggerBuildFinishRead,
-		Delete: resourceBuildTriggerBuildFinishDelete,
+		CreateContext: resourceBuildTriggerBuildFinishCreate,
+		ReadContext:   resourceBuildTriggerBuildFinishRead,
+		DeleteContext: resourceBuildTriggerBuildFinishDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -44,8 +45,13 @@ func resourceBuildTriggerBuildFinish() *schema.Resource {
 	}
 }
 
-func resourceBuildTriggerBuildFinishCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceBuildTriggerBuildFinishCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
+	unlock, err := meta.(*Client).lockBuildType(ctx, d.Get("build_config_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
 	var buildConfigID, triggerBuildConfigID string
 
 	if v, ok := d.GetOk("build_config_id"); ok {
@@ -55,19 +61,19 @@ func resourceBuildTriggerBuildFinishCreate(d *schema.ResourceData, meta interfac
 		triggerBuildConfigID = v.(string)
 	}
 	// validates the Build Configuration exists
-	if _, err := client.BuildTypes.GetByID(buildConfigID); err != nil {
-		return fmt.Errorf("invalid build_config_id '%s' - Build configuration does not exist", buildConfigID)
+	if diags := validateBuildConfigExists(ctx, meta.(*Client), "build_config_id", buildConfigID); diags.HasError() {
+		return diags
 	}
 	// validates the Trigger Build Configuration exists
-	if _, err := client.BuildTypes.GetByID(triggerBuildConfigID); err != nil {
-		return fmt.Errorf("invalid build_config_id '%s' - Build configuration does not exist", triggerBuildConfigID)
+	if diags := validateBuildConfigExists(ctx, meta.(*Client), "source_build_config_id", triggerBuildConfigID); diags.HasError() {
+		return diags
 	}
 
 	ts := client.TriggerService(buildConfigID)
 	opt := api.NewTriggerBuildFinishOptions(false, nil)
 	dt, err := api.NewTriggerBuildFinish(triggerBuildConfigID, opt)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if v, ok := d.GetOk("after_successful_only"); ok {
@@ -83,16 +89,16 @@ func resourceBuildTriggerBuildFinishCreate(d *schema.ResourceData, meta interfac
 	out, err := ts.AddTrigger(dt)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(out.ID())
 
-	return resourceBuildTriggerBuildFinishRead(d, meta)
+	return resourceBuildTriggerBuildFinishRead(ctx, d, meta)
 }
 
-func resourceBuildTriggerBuildFinishRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client).TriggerService(d.Get("build_config_id").(string))
+func resourceBuildTriggerBuildFinishRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx).TriggerService(d.Get("build_config_id").(string))
 
 	ret, err := getTrigger(client, d.Id())
 	if err != nil {
@@ -103,35 +109,46 @@ func resourceBuildTriggerBuildFinishRead(d *schema.ResourceData, meta interface{
 			return nil
 		}
 
-		return err
+		return diag.FromErr(describeAPIError(err, "Build Trigger"))
 	}
 	dt, ok := ret.(*api.TriggerBuildFinish)
 	if !ok {
-		return fmt.Errorf("invalid trigger type when reading build_trigger_build_finish resource")
+		return diag.Errorf("invalid trigger type when reading build_trigger_build_finish resource")
 	}
 	if err := d.Set("build_config_id", dt.BuildTypeID()); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("source_build_config_id", dt.SourceBuildID); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	log.Printf("[INFO] READ: BranchFilter: %s, State: %s", dt.Options.BranchFilter, d.Get("branch_filter"))
 	if err := d.Set("branch_filter", flattenStringSlice(dt.Options.BranchFilter)); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if dt.Options.AfterSuccessfulBuildOnly {
 		if err := d.Set("after_sucessful_only", dt.Options.AfterSuccessfulBuildOnly); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	return nil
 }
 
-func resourceBuildTriggerBuildFinishDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceBuildTriggerBuildFinishDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
+	unlock, err := meta.(*Client).lockBuildType(ctx, d.Get("build_config_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
 	ts := client.TriggerService(d.Get("build_config_id").(string))
 
-	return ts.Delete(d.Id())
+	if err := ts.Delete(d.Id()); err != nil {
+		if !isNotFoundError(err) {
+			return diag.FromErr(describeAPIError(err, "Build Trigger"))
+		}
+	}
+
+	return nil
 }
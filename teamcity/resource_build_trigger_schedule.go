@@ -1,19 +1,20 @@
 package teamcity
 
 import (
-	"fmt"
+	"context"
 	"log"
 
 	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceBuildTriggerSchedule() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceBuildTriggerScheduleCreate,
-		Read:   resourceBuildTriggerScheduleRead,
-		Delete: resourceBuildTriggerScheduleDelete,
+		CreateContext: resourceBuildTriggerScheduleCreate,
+		ReadContext:   resourceBuildTriggerScheduleRead,
+		DeleteContext: resourceBuildTriggerScheduleDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -130,16 +131,21 @@ func resourceBuildTriggerSchedule() *schema.Resource {
 	}
 }
 
-func resourceBuildTriggerScheduleCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceBuildTriggerScheduleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
+	unlock, err := meta.(*Client).lockBuildType(ctx, d.Get("build_config_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
 	var buildConfigID string
 
 	if v, ok := d.GetOk("build_config_id"); ok {
 		buildConfigID = v.(string)
 	}
 	// validates the Build Configuration exists
-	if _, err := client.BuildTypes.GetByID(buildConfigID); err != nil {
-		return fmt.Errorf("invalid build_config_id '%s' - Build configuration does not exist", buildConfigID)
+	if diags := validateBuildConfigExists(ctx, meta.(*Client), "build_config_id", buildConfigID); diags.HasError() {
+		return diags
 	}
 
 	ts := client.TriggerService(buildConfigID)
@@ -153,28 +159,28 @@ func resourceBuildTriggerScheduleCreate(d *schema.ResourceData, meta interface{}
 
 	opt, err := expandTriggerScheduleOptions(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	dt, err := api.NewTriggerSchedule(schedule, buildConfigID, weekday, uint(hour), uint(minute), timezone, rules, opt)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	out, err := ts.AddTrigger(dt)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(out.ID())
 
-	return resourceBuildTriggerScheduleRead(d, meta)
+	return resourceBuildTriggerScheduleRead(ctx, d, meta)
 }
 
-func resourceBuildTriggerScheduleRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client).TriggerService(d.Get("build_config_id").(string))
+func resourceBuildTriggerScheduleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx).TriggerService(d.Get("build_config_id").(string))
 
 	ret, err := getTrigger(client, d.Id())
 	if err != nil {
@@ -185,50 +191,61 @@ func resourceBuildTriggerScheduleRead(d *schema.ResourceData, meta interface{})
 			return nil
 		}
 
-		return err
+		return diag.FromErr(describeAPIError(err, "Build Trigger"))
 	}
 	dt, ok := ret.(*api.TriggerSchedule)
 	if !ok {
-		return fmt.Errorf("invalid trigger type when reading build_trigger_schedule resource")
+		return diag.Errorf("invalid trigger type when reading build_trigger_schedule resource")
 	}
 
 	if err := d.Set("build_config_id", dt.BuildTypeID()); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("schedule", dt.SchedulingPolicy); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("hour", dt.Hour); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("minute", dt.Minute); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("timezone", dt.Timezone); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("rules", flattenStringSlice(dt.Rules)); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if dt.SchedulingPolicy == api.TriggerSchedulingWeekly {
 		if err := d.Set("weekday", dt.Weekday.String()); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 	flatOpt := flattenTriggerScheduleOptions(dt.Options)
 	for k, v := range flatOpt {
 		if err := d.Set(k, v); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 	return nil
 }
 
-func resourceBuildTriggerScheduleDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceBuildTriggerScheduleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
+	unlock, err := meta.(*Client).lockBuildType(ctx, d.Get("build_config_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
 	ts := client.TriggerService(d.Get("build_config_id").(string))
 
-	return ts.Delete(d.Id())
+	if err := ts.Delete(d.Id()); err != nil {
+		if !isNotFoundError(err) {
+			return diag.FromErr(describeAPIError(err, "Build Trigger"))
+		}
+	}
+
+	return nil
 }
 
 func expandTriggerScheduleOptions(d *schema.ResourceData) (*api.TriggerScheduleOptions, error) {
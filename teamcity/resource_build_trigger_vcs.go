@@ -1,18 +1,19 @@
 package teamcity
 
 import (
-	"fmt"
+	"context"
 	"log"
 
 	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceBuildTriggerVcs() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceBuildTriggerVcsCreate,
-		Read:   resourceBuildTriggerVcsRead,
-		Delete: resourceBuildTriggerVcsDelete,
+		CreateContext: resourceBuildTriggerVcsCreate,
+		ReadContext:   resourceBuildTriggerVcsRead,
+		DeleteContext: resourceBuildTriggerVcsDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -39,17 +40,21 @@ func resourceBuildTriggerVcs() *schema.Resource {
 	}
 }
 
-func resourceBuildTriggerVcsCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceBuildTriggerVcsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
+	unlock, err := meta.(*Client).lockBuildType(ctx, d.Get("build_config_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
 	var buildConfigID string
-	var err error
 
 	if v, ok := d.GetOk("build_config_id"); ok {
 		buildConfigID = v.(string)
 	}
 	// validates the Build Configuration exists
-	if _, err := client.BuildTypes.GetByID(buildConfigID); err != nil {
-		return fmt.Errorf("invalid build_config_id '%s' - Build configuration does not exist", buildConfigID)
+	if diags := validateBuildConfigExists(ctx, meta.(*Client), "build_config_id", buildConfigID); diags.HasError() {
+		return diags
 	}
 
 	ts := client.TriggerService(buildConfigID)
@@ -57,10 +62,10 @@ func resourceBuildTriggerVcsCreate(d *schema.ResourceData, meta interface{}) err
 	if v, ok := d.GetOk("rules"); ok {
 		dt, err = api.NewTriggerVcs(expandStringSlice(v.([]interface{})), []string{})
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	} else {
-		return fmt.Errorf("error getting required property 'rules' for vcs trigger")
+		return diag.Errorf("error getting required property 'rules' for vcs trigger")
 	}
 
 	if v, ok := d.GetOk("branch_filter"); ok {
@@ -70,16 +75,16 @@ func resourceBuildTriggerVcsCreate(d *schema.ResourceData, meta interface{}) err
 	out, err := ts.AddTrigger(dt)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(out.ID())
 
-	return resourceBuildTriggerVcsRead(d, meta)
+	return resourceBuildTriggerVcsRead(ctx, d, meta)
 }
 
-func resourceBuildTriggerVcsRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client).TriggerService(d.Get("build_config_id").(string))
+func resourceBuildTriggerVcsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx).TriggerService(d.Get("build_config_id").(string))
 
 	ret, err := getTrigger(client, d.Id())
 	if err != nil {
@@ -90,37 +95,48 @@ func resourceBuildTriggerVcsRead(d *schema.ResourceData, meta interface{}) error
 			return nil
 		}
 
-		return err
+		return diag.FromErr(describeAPIError(err, "Build Trigger"))
 	}
 	dt, ok := ret.(*api.TriggerVcs)
 	if !ok {
-		return fmt.Errorf("invalid trigger type when reading build_trigger_vcs resource")
+		return diag.Errorf("invalid trigger type when reading build_trigger_vcs resource")
 	}
 
 	if err := d.Set("build_config_id", dt.BuildTypeID()); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if len(dt.Rules) > 0 {
 		if err := d.Set("rules", dt.Rules); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	if len(dt.BranchFilter) > 0 {
 		if err := d.Set("branch_filter", dt.BranchFilter); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	return nil
 }
 
-func resourceBuildTriggerVcsDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceBuildTriggerVcsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
+	unlock, err := meta.(*Client).lockBuildType(ctx, d.Get("build_config_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
 	ts := client.TriggerService(d.Get("build_config_id").(string))
 
-	return ts.Delete(d.Id())
+	if err := ts.Delete(d.Id()); err != nil {
+		if !isNotFoundError(err) {
+			return diag.FromErr(describeAPIError(err, "Build Trigger"))
+		}
+	}
+
+	return nil
 }
 
 func getTrigger(c *api.TriggerService, id string) (api.Trigger, error) {
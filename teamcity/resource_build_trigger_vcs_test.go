@@ -1,12 +1,14 @@
 package teamcity_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
 	"testing"
 
 	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/cvbarros/terraform-provider-teamcity/teamcity"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -66,7 +68,7 @@ func TestAccTeamcityBuildTriggerVcs_Update(t *testing.T) {
 
 func testAccCheckTeamcityBuildTriggerDestroy(bt *string, resourceType string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 		return buildTriggerDestroyHelper(s, bt, client, resourceType)
 	}
 }
@@ -94,7 +96,7 @@ func buildTriggerDestroyHelper(s *terraform.State, bt *string, client *api.Clien
 
 func testAccCheckTeamcityBuildTriggerExists(n string, bt *string, t *api.Trigger, exists bool) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 
 		found, err := teamcityBuildTriggerExistsHelper(n, bt, s, client, t)
 		if !exists {
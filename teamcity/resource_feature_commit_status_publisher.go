@@ -2,21 +2,23 @@ package teamcity
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"log"
 	"strings"
 
 	api "github.com/cvbarros/go-teamcity/teamcity"
 	"github.com/cvbarros/terraform-provider-teamcity/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceFeatureCommitStatusPublisher() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceFeatureCommitStatusPublisherCreate,
-		Read:   resourceFeatureCommitStatusPublisherRead,
-		Delete: resourceFeatureCommitStatusPublisherDelete,
+		CreateContext: resourceFeatureCommitStatusPublisherCreate,
+		ReadContext:   resourceFeatureCommitStatusPublisherRead,
+		DeleteContext: resourceFeatureCommitStatusPublisherDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -79,8 +81,13 @@ func resourceFeatureCommitStatusPublisher() *schema.Resource {
 	}
 }
 
-func resourceFeatureCommitStatusPublisherCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceFeatureCommitStatusPublisherCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
+	unlock, err := meta.(*Client).lockBuildType(ctx, d.Get("build_config_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
 	var buildConfigID string
 
 	if v, ok := d.GetOk("build_config_id"); ok {
@@ -88,8 +95,8 @@ func resourceFeatureCommitStatusPublisherCreate(d *schema.ResourceData, meta int
 	}
 
 	// validates the Build Configuration exists
-	if _, err := client.BuildTypes.GetByID(buildConfigID); err != nil {
-		return fmt.Errorf("invalid build_config_id '%s' - Build configuration does not exist", buildConfigID)
+	if diags := validateBuildConfigExists(ctx, meta.(*Client), "build_config_id", buildConfigID); diags.HasError() {
+		return diags
 	}
 
 	srv := client.BuildFeatureService(buildConfigID)
@@ -98,21 +105,21 @@ func resourceFeatureCommitStatusPublisherCreate(d *schema.ResourceData, meta int
 
 	dt, err := buildGithubCommitStatusPublisher(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	out, err := srv.Create(dt)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(out.ID())
 
-	return resourceFeatureCommitStatusPublisherRead(d, meta)
+	return resourceFeatureCommitStatusPublisherRead(ctx, d, meta)
 }
 
-func resourceFeatureCommitStatusPublisherRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client).BuildFeatureService(d.Get("build_config_id").(string))
+func resourceFeatureCommitStatusPublisherRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx).BuildFeatureService(d.Get("build_config_id").(string))
 
 	dt, err := getBuildFeatureCommitPublisher(client, d.Id())
 	if err != nil {
@@ -123,16 +130,16 @@ func resourceFeatureCommitStatusPublisherRead(d *schema.ResourceData, meta inter
 			return nil
 		}
 
-		return err
+		return diag.FromErr(describeAPIError(err, "Build Feature"))
 	}
 
 	if err := d.Set("build_config_id", dt.BuildTypeID()); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	//TODO: Implement other publishers
 	if err := d.Set("publisher", "github"); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	opt := dt.Options.(*api.StatusPublisherGithubOptions)
@@ -147,14 +154,25 @@ func resourceFeatureCommitStatusPublisherRead(d *schema.ResourceData, meta inter
 	}
 
 	optsToSave = append(optsToSave, m)
-	return d.Set("github", optsToSave)
+	return diag.FromErr(d.Set("github", optsToSave))
 }
 
-func resourceFeatureCommitStatusPublisherDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceFeatureCommitStatusPublisherDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
+	unlock, err := meta.(*Client).lockBuildType(ctx, d.Get("build_config_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
 	svr := client.BuildFeatureService(d.Get("build_config_id").(string))
 
-	return svr.Delete(d.Id())
+	if err := svr.Delete(d.Id()); err != nil {
+		if !isNotFoundError(err) {
+			return diag.FromErr(describeAPIError(err, "Build Feature"))
+		}
+	}
+
+	return nil
 }
 
 func buildGithubCommitStatusPublisher(d *schema.ResourceData) (api.BuildFeature, error) {
@@ -1,11 +1,13 @@
 package teamcity_test
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
 
 	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/cvbarros/terraform-provider-teamcity/teamcity"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -67,7 +69,7 @@ func testAccCheckBuildFeatureRecreated(a, b *api.BuildFeature) resource.TestChec
 
 func testAccCheckBuildFeatureDestroy(bt *string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 		return buildFeatureDestroyHelper(s, bt, client)
 	}
 }
@@ -95,7 +97,7 @@ func buildFeatureDestroyHelper(s *terraform.State, bt *string, client *api.Clien
 
 func testAccCheckBuildFeatureExists(n string, bt *string, out *api.BuildFeature) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 		return teamcityBuildFeatureExistsHelper(n, bt, s, client, out)
 	}
 }
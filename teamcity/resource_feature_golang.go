@@ -1,23 +1,29 @@
 package teamcity
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
 
 	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceFeatureGolang() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceFeatureGolangCreate,
-		Read:   resourceFeatureGolangRead,
-		Delete: resourceFeatureGolangDelete,
+		CreateContext: resourceFeatureGolangCreate,
+		ReadContext:   resourceFeatureGolangRead,
+		DeleteContext: resourceFeatureGolangDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
+		CustomizeDiff: func(_ context.Context, _ *schema.ResourceDiff, meta interface{}) error {
+			return requireServerFeatureDiff(meta, featureGolang)
+		},
+
 		Schema: map[string]*schema.Schema{
 			"build_config_id": {
 				Type:     schema.TypeString,
@@ -28,14 +34,19 @@ func resourceFeatureGolang() *schema.Resource {
 	}
 }
 
-func resourceFeatureGolangCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceFeatureGolangCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
+	unlock, err := meta.(*Client).lockBuildType(ctx, d.Get("build_config_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
 
 	buildConfigId := d.Get("build_config_id").(string)
 
 	// validates the Build Configuration exists
-	if _, err := client.BuildTypes.GetByID(buildConfigId); err != nil {
-		return fmt.Errorf("invalid build_config_id %q - Build configuration does not exist", buildConfigId)
+	if diags := validateBuildConfigExists(ctx, meta.(*Client), "build_config_id", buildConfigId); diags.HasError() {
+		return diags
 	}
 
 	service := client.BuildFeatureService(buildConfigId)
@@ -43,21 +54,21 @@ func resourceFeatureGolangCreate(d *schema.ResourceData, meta interface{}) error
 	feature.SetBuildTypeID(buildConfigId)
 	createdService, err := service.Create(feature)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	id := fmt.Sprintf("%s|%s", buildConfigId, createdService.ID())
 	d.SetId(id)
 
-	return resourceFeatureGolangRead(d, meta)
+	return resourceFeatureGolangRead(ctx, d, meta)
 }
 
-func resourceFeatureGolangRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceFeatureGolangRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
 
 	id, err := ParseFeatureGolangID(d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	service := client.BuildFeatureService(id.BuildConfigID)
@@ -69,7 +80,7 @@ func resourceFeatureGolangRead(d *schema.ResourceData, meta interface{}) error {
 			return nil
 		}
 
-		return err
+		return diag.FromErr(describeAPIError(err, "Build Feature"))
 	}
 
 	d.Set("build_config_id", id.BuildConfigID)
@@ -77,18 +88,23 @@ func resourceFeatureGolangRead(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func resourceFeatureGolangDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceFeatureGolangDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
+	unlock, err := meta.(*Client).lockBuildType(ctx, d.Get("build_config_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
 
 	id, err := ParseFeatureGolangID(d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	service := client.BuildFeatureService(id.BuildConfigID)
 	if err := service.Delete(id.FeatureID); err != nil {
 		if !isNotFoundError(err) {
-			return err
+			return diag.FromErr(describeAPIError(err, "Build Feature"))
 		}
 	}
 
@@ -1,11 +1,11 @@
 package teamcity_test
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
 
-	api "github.com/cvbarros/go-teamcity/teamcity"
 	"github.com/cvbarros/terraform-provider-teamcity/teamcity"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -35,7 +35,7 @@ func TestAccTeamcityFeatureGolang_Basic(t *testing.T) {
 }
 
 func testAccCheckBuildFeatureGolangDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*api.Client)
+	client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "teamcity_feature_github" {
 			continue
@@ -62,7 +62,7 @@ func testAccCheckBuildFeatureGolangDestroy(s *terraform.State) error {
 
 func testAccCheckBuildFeatureGolangExists(resourceName string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 		rs, ok := s.RootModule().Resources[resourceName]
 		if !ok {
 			return fmt.Errorf("Not found: %s", resourceName)
@@ -1,6 +1,7 @@
 package teamcity
 
 import (
+	"context"
 	"fmt"
 	"hash/crc32"
 	"log"
@@ -8,16 +9,17 @@ import (
 	"strings"
 
 	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceGroup() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceGroupCreate,
-		Read:   resourceGroupRead,
-		Delete: resourceGroupDelete,
+		CreateContext: resourceGroupCreate,
+		ReadContext:   resourceGroupRead,
+		DeleteContext: resourceGroupDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceGroupImport,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -41,8 +43,8 @@ func resourceGroup() *schema.Resource {
 	}
 }
 
-func resourceGroupCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
 	var key, name, description string
 
 	if v, ok := d.GetOk("key"); ok {
@@ -61,24 +63,24 @@ func resourceGroupCreate(d *schema.ResourceData, meta interface{}) error {
 		generateKey, err := generateKey(name)
 
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		key = *generateKey
 	}
 
 	newGroup, err := api.NewGroup(key, name, description)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	created, err := client.Groups.Create(newGroup)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(created.Key)
 
-	return resourceGroupRead(d, meta)
+	return resourceGroupRead(ctx, d, meta)
 }
 
 func generateKey(name string) (*string, error) {
@@ -93,8 +95,8 @@ func generateKey(name string) (*string, error) {
 	return &generatedKey, nil
 }
 
-func resourceGroupRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
 
 	dt, err := client.Groups.GetByKey(d.Id())
 	if err != nil {
@@ -105,30 +107,29 @@ func resourceGroupRead(d *schema.ResourceData, meta interface{}) error {
 			return nil
 		}
 
-		return err
+		return diag.FromErr(describeAPIError(err, "Group"))
 	}
 	if err := d.Set("key", dt.Key); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("name", dt.Name); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("description", dt.Description); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceGroupDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
 
-	return client.Groups.Delete(d.Id())
-}
-
-func resourceGroupImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	if err := resourceGroupRead(d, meta); err != nil {
-		return nil, err
+	if err := client.Groups.Delete(d.Id()); err != nil {
+		if !isNotFoundError(err) {
+			return diag.FromErr(describeAPIError(err, "Group"))
+		}
 	}
-	return []*schema.ResourceData{d}, nil
+
+	return nil
 }
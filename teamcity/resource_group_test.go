@@ -1,6 +1,7 @@
 package teamcity_test
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
@@ -12,6 +13,7 @@ import (
 	"regexp"
 
 	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/cvbarros/terraform-provider-teamcity/teamcity"
 )
 
 func TestAccGroupCreate_Basic(t *testing.T) {
@@ -77,7 +79,7 @@ func generateKey(name string) string {
 }
 
 func testAccCheckGroupDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*api.Client)
+	client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 	return buildGroupDestroyHelper(s, client)
 }
 
@@ -103,7 +105,7 @@ func buildGroupDestroyHelper(s *terraform.State, client *api.Client) error {
 
 func testAccCheckGroupExists(n string, out *api.Group) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 		return groupExistsHelper(n, s, client, out)
 	}
 }
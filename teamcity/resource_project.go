@@ -1,23 +1,36 @@
 package teamcity
 
 import (
+	"context"
 	"log"
+	"time"
 
 	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceProject() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceProjectCreate,
-		Read:   resourceProjectRead,
-		Update: resourceProjectUpdate,
-		Delete: resourceProjectDelete,
+		CreateContext: resourceProjectCreate,
+		ReadContext:   resourceProjectRead,
+		UpdateContext: resourceProjectUpdate,
+		DeleteContext: resourceProjectDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceProjectImport,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
+
+		CustomizeDiff: validateParameterSpecsDiff,
+
 		Schema: map[string]*schema.Schema{
+			"external_id": externalIDSchema(),
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -42,36 +55,52 @@ func resourceProject() *schema.Resource {
 				Type:     schema.TypeMap,
 				Optional: true,
 			},
+			"param": parameterSpecSchema(),
+			"inherited_params": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
 		},
 	}
 }
 
-func resourceProjectCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceProjectCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
 
 	name := d.Get("name").(string)
 	parentProjectId := d.Get("parent_id").(string)
 
 	newProj, err := api.NewProject(name, "", parentProjectId)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
+	}
+	if v, ok := d.GetOk("external_id"); ok {
+		newProj.ID = v.(string)
 	}
 
 	created, err := client.Projects.Create(newProj)
 	if err != nil {
-		return err
+		return diag.FromErr(describeAPIError(err, "Project"))
 	}
 
 	d.SetId(created.ID)
 
-	return resourceProjectUpdate(d, client)
+	return resourceProjectUpdate(ctx, d, meta)
 }
 
-func resourceProjectUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceProjectUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
+	if v, ok := d.GetOk("external_id"); ok && v.(string) != d.Id() {
+		if err := changeExternalID(meta.(*Client).rest(ctx), projectPath(d.Id()), v.(string)); err != nil {
+			return diag.FromErr(describeAPIError(err, "Project"))
+		}
+		d.SetId(v.(string))
+	}
+
 	dt, err := client.Projects.GetByID(d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(describeAPIError(err, "Project"))
 	}
 
 	if d.HasChange("name") {
@@ -88,20 +117,28 @@ func resourceProjectUpdate(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
-	dt.Parameters, err = expandParameterCollection(d)
+	// the parameters are only written by setParameters below: go-teamcity would replace them without the `param`
+	// blocks, dropping passwords until they're set again
+	dt.Parameters = api.NewParametersEmpty()
+
+	_, err = client.Projects.Update(dt)
 	if err != nil {
-		return err
+		return diag.FromErr(describeAPIError(err, "Project"))
 	}
 
-	_, err = client.Projects.Update(dt)
+	// go-teamcity doesn't support parameter specifications, and replaces passwords with the masked values it read
+	params, err := expandParameterSpecs(d)
 	if err != nil {
-		return nil
+		return diag.FromErr(err)
+	}
+	if err := setParameters(meta.(*Client).rest(ctx), projectPath(dt.ID), params); err != nil {
+		return diag.FromErr(describeAPIError(err, "Project"))
 	}
-	return resourceProjectRead(d, meta)
+	return resourceProjectRead(ctx, d, meta)
 }
 
-func resourceProjectRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceProjectRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
 
 	dt, err := client.Projects.GetByID(d.Id())
 	if err != nil {
@@ -112,9 +149,10 @@ func resourceProjectRead(d *schema.ResourceData, meta interface{}) error {
 			return nil
 		}
 
-		return err
+		return diag.FromErr(describeAPIError(err, "Project"))
 	}
 
+	d.Set("external_id", dt.ID)
 	d.Set("name", dt.Name)
 	d.Set("description", dt.Description)
 	parentProjectId := dt.ParentProjectID
@@ -123,20 +161,23 @@ func resourceProjectRead(d *schema.ResourceData, meta interface{}) error {
 	}
 	d.Set("parent_id", parentProjectId)
 
-	return flattenParameterCollection(d, dt.Parameters)
+	params, err := getParameters(meta.(*Client).rest(ctx), projectPath(d.Id()))
+	if err != nil {
+		return diag.FromErr(describeAPIError(err, "Project"))
+	}
+	return diag.FromErr(flattenParameterSpecs(d, params))
 }
 
-func resourceProjectDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceProjectDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
 	log.Printf("[DEBUG]: resourceProjectDelete - Destroying project %v", d.Id())
-	err := client.Projects.Delete(d.Id())
-	log.Printf("[INFO]: resourceProjectDelete - Destroyed project %v", d.Id())
-	return err
-}
-
-func resourceProjectImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	if err := resourceProjectRead(d, meta); err != nil {
-		return nil, err
+	// deleting a Project also deletes the Build Configurations it contains
+	defer meta.(*Client).invalidateBuildTypes()
+	if err := client.Projects.Delete(d.Id()); err != nil {
+		if !isNotFoundError(err) {
+			return diag.FromErr(describeAPIError(err, "Project"))
+		}
 	}
-	return []*schema.ResourceData{d}, nil
+	log.Printf("[INFO]: resourceProjectDelete - Destroyed project %v", d.Id())
+	return nil
 }
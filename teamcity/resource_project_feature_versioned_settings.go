@@ -1,25 +1,40 @@
 package teamcity
 
 import (
-	"fmt"
+	"context"
 	"log"
-	"strings"
+	"time"
 
 	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceProjectFeatureVersionedSettings() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceProjectFeatureVersionedSettingsCreate,
-		Read:   resourceProjectFeatureVersionedSettingsRead,
-		Update: resourceProjectFeatureVersionedSettingsUpdate,
-		Delete: resourceProjectFeatureVersionedSettingsDelete,
+		CreateContext: resourceProjectFeatureVersionedSettingsCreate,
+		ReadContext:   resourceProjectFeatureVersionedSettingsRead,
+		UpdateContext: resourceProjectFeatureVersionedSettingsUpdate,
+		DeleteContext: resourceProjectFeatureVersionedSettingsDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		CustomizeDiff: func(_ context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+			if diff.Get("credentials_storage_type").(string) == string(api.CredentialsStorageTypeCredentialsJSON) {
+				return requireServerFeatureDiff(meta, featureCredentialsJSON)
+			}
+			return nil
+		},
+
 		Schema: map[string]*schema.Schema{
 			"project_id": {
 				Type:     schema.TypeString,
@@ -87,8 +102,13 @@ func resourceProjectFeatureVersionedSettings() *schema.Resource {
 	}
 }
 
-func resourceProjectFeatureVersionedSettingsCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceProjectFeatureVersionedSettingsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
+	unlock, err := meta.(*Client).lockProject(ctx, d.Get("project_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
 
 	projectId := d.Get("project_id").(string)
 	service := client.ProjectFeatureService(projectId)
@@ -111,27 +131,32 @@ func resourceProjectFeatureVersionedSettingsCreate(d *schema.ResourceData, meta
 	// however the ID returned eventually gets overwritten
 	// so we need to look it up using the type
 	if _, err := service.Create(feature); err != nil {
-		return err
+		return diag.FromErr(describeAPIError(err, "Project Feature"))
 	}
 
 	d.SetId(projectId)
 
-	return resourceProjectFeatureVersionedSettingsRead(d, meta)
+	return resourceProjectFeatureVersionedSettingsRead(ctx, d, meta)
 }
 
-func resourceProjectFeatureVersionedSettingsUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceProjectFeatureVersionedSettingsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
+	unlock, err := meta.(*Client).lockProject(ctx, d.Get("project_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
 
 	projectId := d.Id()
 	service := client.ProjectFeatureService(projectId)
 	feature, err := service.GetByType("versionedSettings")
 	if err != nil {
-		return err
+		return diag.FromErr(describeAPIError(err, "Project Feature"))
 	}
 
 	vcsFeature, ok := feature.(*api.ProjectFeatureVersionedSettings)
 	if !ok {
-		return fmt.Errorf("Expected a VersionedSettings Feature but wasn't!")
+		return diag.Errorf("Expected a VersionedSettings Feature but wasn't!")
 	}
 
 	if d.HasChange("build_settings") {
@@ -166,31 +191,31 @@ func resourceProjectFeatureVersionedSettingsUpdate(d *schema.ResourceData, meta
 	}
 
 	if _, err := service.Update(vcsFeature); err != nil {
-		return err
+		return diag.FromErr(describeAPIError(err, "Project Feature"))
 	}
 
-	return resourceProjectFeatureVersionedSettingsRead(d, meta)
+	return resourceProjectFeatureVersionedSettingsRead(ctx, d, meta)
 }
 
-func resourceProjectFeatureVersionedSettingsRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceProjectFeatureVersionedSettingsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
 
 	projectId := d.Id()
 	service := client.ProjectFeatureService(projectId)
 	feature, err := service.GetByType("versionedSettings")
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
+		if isNotFoundError(err) {
 			log.Printf("[DEBUG] Project Feature Versioned Settings was not found - removing from state!")
 			d.SetId("")
 			return nil
 		}
 
-		return err
+		return diag.FromErr(describeAPIError(err, "Project Feature"))
 	}
 
 	vcsFeature, ok := feature.(*api.ProjectFeatureVersionedSettings)
 	if !ok {
-		return fmt.Errorf("Expected a VersionedSettings Feature but wasn't!")
+		return diag.Errorf("Expected a VersionedSettings Feature but wasn't!")
 	}
 
 	d.Set("build_settings", string(vcsFeature.Options.BuildSettings))
@@ -203,7 +228,7 @@ func resourceProjectFeatureVersionedSettingsRead(d *schema.ResourceData, meta in
 
 	flattenedContextParameters := flattenContextParameters(vcsFeature.Options.ContextParameters)
 	if err := d.Set("context_parameters", flattenedContextParameters); err != nil {
-		return fmt.Errorf("Error setting `context_parameters`: %+v", err)
+		return diag.Errorf("Error setting `context_parameters`: %+v", err)
 	}
 
 	credentialsStorageType := "scrambled"
@@ -215,22 +240,33 @@ func resourceProjectFeatureVersionedSettingsRead(d *schema.ResourceData, meta in
 	return nil
 }
 
-func resourceProjectFeatureVersionedSettingsDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceProjectFeatureVersionedSettingsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
+	unlock, err := meta.(*Client).lockProject(ctx, d.Get("project_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
 
 	projectId := d.Id()
 	service := client.ProjectFeatureService(projectId)
 	feature, err := service.GetByType("versionedSettings")
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
+		if isNotFoundError(err) {
 			// already gone
 			return nil
 		}
 
-		return err
+		return diag.FromErr(describeAPIError(err, "Project Feature"))
 	}
 
-	return service.Delete(feature.ID())
+	if err := service.Delete(feature.ID()); err != nil {
+		if !isNotFoundError(err) {
+			return diag.FromErr(describeAPIError(err, "Project Feature"))
+		}
+	}
+
+	return nil
 }
 
 func expandContextParameters(input map[string]interface{}) map[string]string {
@@ -1,11 +1,13 @@
 package teamcity_test
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
 
 	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/cvbarros/terraform-provider-teamcity/teamcity"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -184,7 +186,7 @@ func TestAccTeamcityProjectVersionedSettings_CredentialsStorageTypeSettings(t *t
 
 func testAccCheckTeamCityProjectVersionedSettingsExists(resourceName string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 		rs, ok := s.RootModule().Resources[resourceName]
 		if !ok {
 			return fmt.Errorf("Not found: %s", resourceName)
@@ -205,7 +207,7 @@ func testAccCheckTeamCityProjectVersionedSettingsExists(resourceName string) res
 }
 
 func testAccCheckTeamCityProjectVersionedSettingsDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*api.Client)
+	client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 	for _, r := range s.RootModule().Resources {
 		if r.Type != "teamcity_project_feature_versioned_settings" {
 			continue
@@ -1,11 +1,13 @@
 package teamcity_test
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
 
 	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/cvbarros/terraform-provider-teamcity/teamcity"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -64,6 +66,85 @@ func TestAccTeamcityProject_Full(t *testing.T) {
 	})
 }
 
+func TestAccTeamcityProject_ParameterSpecs(t *testing.T) {
+	resName := "teamcity_project.testproj"
+	var p api.Project
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckTeamcityProjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTeamcityProjectParameterSpecs,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckTeamcityProjectExists(resName, &p),
+					resource.TestCheckResourceAttr(resName, "config_params.plain", "value"),
+					resource.TestCheckResourceAttr(resName, "param.#", "3"),
+					resource.TestCheckTypeSetElemNestedAttrs(resName, "param.*", map[string]string{
+						"name":    "env.DEPLOY_TOKEN",
+						"value":   "hunter2",
+						"type":    "password",
+						"display": "hidden",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs(resName, "param.*", map[string]string{
+						"name":      "environment",
+						"value":     "staging",
+						"type":      "select",
+						"label":     "Environment",
+						"display":   "prompt",
+						"options.#": "2",
+						"options.0": "staging",
+						"options.1": "production",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs(resName, "param.*", map[string]string{
+						"name":             "system.version",
+						"value":            "1.0.0",
+						"type":             "text",
+						"read_only":        "true",
+						"validation_regex": "\\d+\\.\\d+\\.\\d+",
+					}),
+				),
+			},
+			{
+				// password values aren't returned by TeamCity, which mustn't cause a diff
+				Config:   testAccTeamcityProjectParameterSpecs,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccTeamcityProject_ExternalID(t *testing.T) {
+	resName := "teamcity_project.testproj"
+	var p api.Project
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckTeamcityProjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccTeamcityProjectExternalID, "Platform_Services"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckTeamcityProjectExists(resName, &p),
+					resource.TestCheckResourceAttr(resName, "id", "Platform_Services"),
+					resource.TestCheckResourceAttr(resName, "external_id", "Platform_Services"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccTeamcityProjectExternalID, "Platform_Backend"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckTeamcityProjectExists(resName, &p),
+					resource.TestCheckResourceAttr(resName, "id", "Platform_Backend"),
+					resource.TestCheckResourceAttr(resName, "external_id", "Platform_Backend"),
+					resource.TestCheckResourceAttr(resName, "config_params.kept", "value"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccTeamcityProject_Parent(t *testing.T) {
 	parentRes := "teamcity_project.parent"
 	childRes := "teamcity_project.child"
@@ -154,7 +235,7 @@ func testAccCheckProjectParameter(dt *api.Project, paramType string, paramName s
 
 func testAccCheckTeamcityProjectExists(n string, project *api.Project) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 		return teamcityProjectExistsHelper(n, s, client, project)
 	}
 }
@@ -181,7 +262,7 @@ func teamcityProjectExistsHelper(n string, s *terraform.State, client *api.Clien
 }
 
 func testAccCheckTeamcityProjectDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*api.Client)
+	client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 	return teamcityProjectDestroyHelper(s, client)
 }
 
@@ -255,3 +336,47 @@ resource "teamcity_project" "testproj" {
 	}
 }
 `
+
+const testAccTeamcityProjectParameterSpecs = `
+resource "teamcity_project" "testproj" {
+  name = "test_project"
+
+  config_params = {
+    plain = "value"
+  }
+
+  param {
+    name    = "env.DEPLOY_TOKEN"
+    value   = "hunter2"
+    type    = "password"
+    display = "hidden"
+  }
+
+  param {
+    name    = "environment"
+    value   = "staging"
+    type    = "select"
+    label   = "Environment"
+    display = "prompt"
+    options = ["staging", "production"]
+  }
+
+  param {
+    name             = "system.version"
+    value            = "1.0.0"
+    read_only        = true
+    validation_regex = "\\d+\\.\\d+\\.\\d+"
+  }
+}
+`
+
+const testAccTeamcityProjectExternalID = `
+resource "teamcity_project" "testproj" {
+  name        = "testproj"
+  external_id = "%s"
+
+  config_params = {
+    kept = "value"
+  }
+}
+`
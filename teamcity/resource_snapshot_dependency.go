@@ -1,18 +1,20 @@
 package teamcity
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceSnapshotDependency() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceSnapshotDependencyCreate,
-		Read:   resourceSnapshotDependencyRead,
-		Delete: resourceSnapshotDependencyDelete,
+		CreateContext: resourceSnapshotDependencyCreate,
+		ReadContext:   resourceSnapshotDependencyRead,
+		DeleteContext: resourceSnapshotDependencyDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -32,36 +34,37 @@ func resourceSnapshotDependency() *schema.Resource {
 	}
 }
 
-func resourceSnapshotDependencyCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceSnapshotDependencyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	r := meta.(*Client).rest(ctx)
+	unlock, err := meta.(*Client).lockBuildType(ctx, d.Get("build_config_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
 	var buildConfigID string
 
 	if v, ok := d.GetOk("build_config_id"); ok {
 		buildConfigID = v.(string)
 	}
 	// validates the Build Configuration exists
-	if _, err := client.BuildTypes.GetByID(buildConfigID); err != nil {
-		return fmt.Errorf("invalid build_config_id '%s' - Build configuration does not exist", buildConfigID)
+	if diags := validateBuildConfigExists(ctx, meta.(*Client), "build_config_id", buildConfigID); diags.HasError() {
+		return diags
 	}
 
-	depService := client.DependencyService(buildConfigID)
 	dep := api.NewSnapshotDependency(d.Get("source_build_config_id").(string))
 
-	out, err := depService.AddSnapshotDependency(dep)
-
-	if err != nil {
-		return err
+	var out api.SnapshotDependency
+	if err := r.post(snapshotDependenciesPath(buildConfigID), dep, &out); err != nil {
+		return diag.FromErr(describeAPIError(err, "Snapshot Dependency"))
 	}
 
 	d.SetId(out.ID)
 
-	return resourceSnapshotDependencyRead(d, meta)
+	return resourceSnapshotDependencyRead(ctx, d, meta)
 }
 
-func resourceSnapshotDependencyRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client).DependencyService(d.Get("build_config_id").(string))
-
-	dt, err := getSnapshotDependency(client, d.Id())
+func resourceSnapshotDependencyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	dt, err := getSnapshotDependency(meta.(*Client).rest(ctx), d.Get("build_config_id").(string), d.Id())
 	if err != nil {
 		// handles this being deleted outside of TF
 		if isNotFoundError(err) {
@@ -70,29 +73,41 @@ func resourceSnapshotDependencyRead(d *schema.ResourceData, meta interface{}) er
 			return nil
 		}
 
-		return err
+		return diag.FromErr(describeAPIError(err, "Snapshot Dependency"))
 	}
 
 	if err := d.Set("build_config_id", dt.BuildTypeID); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	return d.Set("source_build_config_id", dt.SourceBuildType.ID)
+	return diag.FromErr(d.Set("source_build_config_id", dt.SourceBuildType.ID))
 }
 
-func resourceSnapshotDependencyDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
-	dep := client.DependencyService(d.Get("build_config_id").(string))
+func resourceSnapshotDependencyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	r := meta.(*Client).rest(ctx)
+	unlock, err := meta.(*Client).lockBuildType(ctx, d.Get("build_config_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
+	if err := r.delete(fmt.Sprintf("%s/%s", snapshotDependenciesPath(d.Get("build_config_id").(string)), d.Id())); err != nil {
+		if !isNotFoundError(err) {
+			return diag.FromErr(describeAPIError(err, "Snapshot Dependency"))
+		}
+	}
 
-	return dep.DeleteSnapshot(d.Id())
+	return nil
 }
 
-func getSnapshotDependency(c *api.DependencyService, id string) (*api.SnapshotDependency, error) {
+func snapshotDependenciesPath(buildConfigID string) string {
+	return buildConfigPath(buildConfigID) + "/snapshot-dependencies"
+}
 
-	dt, err := c.GetSnapshotByID(id)
-	if err != nil {
+func getSnapshotDependency(r *restClient, buildConfigID string, id string) (*api.SnapshotDependency, error) {
+	var out api.SnapshotDependency
+	if err := r.get(fmt.Sprintf("%s/%s", snapshotDependenciesPath(buildConfigID), id), &out); err != nil {
 		return nil, err
 	}
-
-	return dt, nil
+	out.BuildTypeID = buildConfigID
+	return &out, nil
 }
@@ -1,11 +1,13 @@
 package teamcity_test
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
 
 	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/cvbarros/terraform-provider-teamcity/teamcity"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -91,7 +93,7 @@ func testAccCheckSnapshotSourceBuildType(n string, sd *api.SnapshotDependency) r
 
 func testAccCheckTeamcitySnapshotDependencyDestroy(bt *string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 		return snapshotDependencyDestroyHelper(s, bt, client)
 	}
 }
@@ -119,7 +121,7 @@ func snapshotDependencyDestroyHelper(s *terraform.State, bt *string, client *api
 
 func testAccCheckTeamcitySnapshotDependencyExists(n string, bt *string, snap *api.SnapshotDependency) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 		return teamcitySnapshotDependencyExistsHelper(n, bt, s, client, snap)
 	}
 }
@@ -1,25 +1,35 @@
 package teamcity
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceVcsRootGit() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVcsRootGitCreateUpdate,
-		Read:   resourceVcsRootGitRead,
-		Update: resourceVcsRootGitCreateUpdate,
-		Delete: resourceVcsRootGitDelete,
+		CreateContext: resourceVcsRootGitCreateUpdate,
+		ReadContext:   resourceVcsRootGitRead,
+		UpdateContext: resourceVcsRootGitCreateUpdate,
+		DeleteContext: resourceVcsRootGitDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -179,8 +189,8 @@ var expandCleanFilesPolicyMap = map[string]string{
 
 var flattenCleanFilesPolicyMap = reverseMap(expandCleanFilesPolicyMap)
 
-func resourceVcsRootGitCreateUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceVcsRootGitCreateUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
 	projectID := d.Get("project_id").(string)
 	var gitVcs *api.GitVcsRoot
 	var name string
@@ -189,7 +199,7 @@ func resourceVcsRootGitCreateUpdate(d *schema.ResourceData, meta interface{}) er
 	vcsOpts, err := expandGitVcsRootOptions(d)
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if v, ok := d.GetOk("name"); ok {
@@ -210,23 +220,23 @@ func resourceVcsRootGitCreateUpdate(d *schema.ResourceData, meta interface{}) er
 	if d.IsNewResource() {
 		log.Printf("[INFO] detected new VCS Root resource, creating.")
 		if gitVcs, err = api.NewGitVcsRoot(projectID, name, vcsOpts); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		if modificationCheckInterval > 0 {
 			gitVcs.SetModificationCheckInterval(int32(modificationCheckInterval))
 		}
 		created, err := client.VcsRoots.Create(projectID, gitVcs)
 		if err != nil {
-			return err
+			return diag.FromErr(describeAPIError(err, "VCS Root"))
 		}
 		d.SetId(created.ID)
 
-		return resourceVcsRootGitRead(d, meta)
+		return resourceVcsRootGitRead(ctx, d, meta)
 	}
 	log.Printf("[INFO] Updating VCS Root resource.")
 	vcs, err := client.VcsRoots.GetByID(d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(describeAPIError(err, "VCS Root"))
 	}
 
 	gitVcs = vcs.(*api.GitVcsRoot)
@@ -234,6 +244,9 @@ func resourceVcsRootGitCreateUpdate(d *schema.ResourceData, meta interface{}) er
 		gitVcs.SetName(name)
 	}
 	if d.HasChange("project_id") {
+		if err := moveVcsRoot(meta.(*Client).rest(ctx), d.Id(), projectID); err != nil {
+			return diag.FromErr(describeAPIError(err, "VCS Root"))
+		}
 		gitVcs.SetProjectID(projectID)
 	}
 	if d.HasChange("modification_check_interval") {
@@ -276,14 +289,14 @@ func resourceVcsRootGitCreateUpdate(d *schema.ResourceData, meta interface{}) er
 
 	_, err = client.VcsRoots.Update(gitVcs)
 	if err != nil {
-		return err
+		return diag.FromErr(describeAPIError(err, "VCS Root"))
 	}
 
-	return resourceVcsRootGitRead(d, meta)
+	return resourceVcsRootGitRead(ctx, d, meta)
 }
 
-func resourceVcsRootGitRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceVcsRootGitRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
 	vcsID := d.Id()
 
 	vcs, err := client.VcsRoots.GetByID(vcsID)
@@ -295,80 +308,84 @@ func resourceVcsRootGitRead(d *schema.ResourceData, meta interface{}) error {
 			return nil
 		}
 
-		return err
+		return diag.FromErr(describeAPIError(err, "VCS Root"))
 	}
 
 	dt, ok := vcs.(*api.GitVcsRoot)
 	if !ok {
-		return fmt.Errorf("VCS with ID = %s has a type mismatch, not a Git VCS. Actual type: %s", vcsID, vcs.VcsName())
+		return diag.Errorf("VCS with ID = %s has a type mismatch, not a Git VCS. Actual type: %s", vcsID, vcs.VcsName())
 	}
 
 	if err := d.Set("project_id", dt.Project.ID); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if err := d.Set("name", dt.Name()); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if dt.ModificationCheckInterval() != nil {
 		v := *(dt.ModificationCheckInterval())
 		if err := d.Set("modification_check_interval", int(v)); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	if err := d.Set("fetch_url", dt.Options.FetchURL); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if err := d.Set("push_url", dt.Options.PushURL); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if err := d.Set("default_branch", dt.Options.DefaultBranch); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if len(dt.Options.BranchSpec) > 0 {
 		if err := d.Set("branches", flattenStringSlice(dt.Options.BranchSpec)); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	if auth, err := flattenGitVcsRootAuth(d, dt.Options); err != nil {
 		if err := d.Set("auth", auth); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	if err := d.Set("submodule_checkout", dt.Options.SubModuleCheckout); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if err := d.Set("enable_branch_spec_tags", dt.Options.EnableTagsInBranchSpec); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if err := d.Set("username_style", flattenUsernameStyleMap[string(dt.Options.UsernameStyle)]); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if agent, err := flattenGitAgentSettings(d, dt.Options.AgentSettings); err != nil {
 		if err := d.Set("agent", agent); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	return nil
 }
 
-func resourceVcsRootGitDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+func resourceVcsRootGitDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).API(ctx)
 	log.Printf("[DEBUG]: resourceVcsRootGitDelete - Destroying vcs root %v", d.Id())
-	err := client.VcsRoots.Delete(d.Id())
+	if err := client.VcsRoots.Delete(d.Id()); err != nil {
+		if !isNotFoundError(err) {
+			return diag.FromErr(describeAPIError(err, "VCS Root"))
+		}
+	}
 	log.Printf("[INFO]: resourceVcsRootGitDelete - Destroyed vcs root %v", d.Id())
-	return err
+	return nil
 }
 
 func expandGitVcsRootOptions(d *schema.ResourceData) (*api.GitVcsRootOptions, error) {
@@ -1,12 +1,15 @@
 package teamcity_test
 
 import (
+	"context"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 	"testing"
 
 	api "github.com/cvbarros/go-teamcity/teamcity"
+	"github.com/cvbarros/terraform-provider-teamcity/teamcity"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -189,6 +192,23 @@ func TestAccVcsRootGit_AgentSettings(t *testing.T) {
 	})
 }
 
+func TestAccVcsRootGit_MoveAttached(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVcsRootGitDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccVcsRootGitMoveAttached, "parent"),
+			},
+			{
+				Config:      fmt.Sprintf(testAccVcsRootGitMoveAttached, "sibling"),
+				ExpectError: regexp.MustCompile("it's attached to Build Configurations outside of it"),
+			},
+		},
+	})
+}
+
 func TestAccVcsRootGit_Delete(t *testing.T) {
 	resName := "teamcity_vcs_root_git.git_test"
 
@@ -207,7 +227,7 @@ func TestAccVcsRootGit_Delete(t *testing.T) {
 
 func testAccCheckVcsRootGitExists(name string, out *api.GitVcsRoot) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 		return vcsRootGitExistsHelper(s, client, out)
 	}
 }
@@ -231,7 +251,7 @@ func vcsRootGitExistsHelper(s *terraform.State, client *api.Client, out *api.Git
 }
 
 func testAccCheckVcsRootGitDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*api.Client)
+	client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 	return vcsRootGitDestroyHelper(s, client)
 }
 
@@ -257,7 +277,7 @@ func vcsRootGitDestroyHelper(s *terraform.State, client *api.Client) error {
 
 func testAccCheckVcsRootGitAgentSettings(vcs *api.GitVcsRoot, expected map[string]string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*api.Client)
+		client := testAccProvider.Meta().(*teamcity.Client).API(context.Background())
 		dt, err := client.VcsRoots.GetByID((*vcs).ID)
 		if err != nil {
 			return err
@@ -397,3 +417,35 @@ resource "teamcity_vcs_root_git" "git_test" {
 	}
 }
 `
+
+const testAccVcsRootGitMoveAttached = `
+resource "teamcity_project" "parent" {
+  name = "vcs_root_project"
+}
+
+resource "teamcity_project" "child" {
+  name = "child"
+  parent_id = teamcity_project.parent.id
+}
+
+resource "teamcity_project" "sibling" {
+  name = "sibling"
+  parent_id = teamcity_project.parent.id
+}
+
+resource "teamcity_vcs_root_git" "git_test" {
+	name = "application"
+	project_id = teamcity_project.%s.id
+	fetch_url = "https://github.com/kelseyhightower/nocode"
+	default_branch = "refs/head/master"
+}
+
+resource "teamcity_build_config" "build_config" {
+	name = "build config"
+	project_id = teamcity_project.child.id
+
+	vcs_root {
+		id = teamcity_vcs_root_git.git_test.id
+	}
+}
+`
@@ -0,0 +1,76 @@
+package teamcity
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// ServerVersion is the version of the TeamCity server the provider is connected to
+type ServerVersion struct {
+	Major       int
+	Minor       int
+	Patch       int
+	BuildNumber string
+}
+
+// e.g. "2019.2.2 (build 71923)" or "2020.1 (build 78475)"
+var serverVersionPattern = regexp.MustCompile(`^(\d+)\.(\d+)(?:\.(\d+))?(?:\s+\(build (\d+)\))?`)
+
+// ParseServerVersion parses the version reported by the TeamCity server, e.g. "2019.2.2 (build 71923)"
+func ParseServerVersion(version string) (*ServerVersion, error) {
+	matches := serverVersionPattern.FindStringSubmatch(version)
+	if matches == nil {
+		return nil, fmt.Errorf("unable to parse TeamCity server version %q", version)
+	}
+
+	v := &ServerVersion{
+		BuildNumber: matches[4],
+	}
+	v.Major, _ = strconv.Atoi(matches[1])
+	v.Minor, _ = strconv.Atoi(matches[2])
+	if matches[3] != "" {
+		v.Patch, _ = strconv.Atoi(matches[3])
+	}
+	return v, nil
+}
+
+// AtLeast returns whether this version is the same as, or newer than, major.minor
+func (v ServerVersion) AtLeast(major, minor int) bool {
+	if v.Major != major {
+		return v.Major > major
+	}
+	return v.Minor >= minor
+}
+
+func (v ServerVersion) String() string {
+	out := fmt.Sprintf("%d.%d", v.Major, v.Minor)
+	if v.Patch > 0 {
+		out = fmt.Sprintf("%s.%d", out, v.Patch)
+	}
+	if v.BuildNumber != "" {
+		out = fmt.Sprintf("%s (build %s)", out, v.BuildNumber)
+	}
+	return out
+}
+
+// serverFeature is a capability which isn't available on every version of TeamCity
+type serverFeature struct {
+	description string
+	major       int
+	minor       int
+}
+
+var (
+	featureCredentialsJSON = serverFeature{description: "storing credentials outside of VCS (`credentialsJSON`)", major: 2017, minor: 1}
+	featureGolang          = serverFeature{description: "the Golang build feature", major: 2019, minor: 1}
+)
+
+// requireServerFeatureDiff fails a plan when the connected server is known not to support feature
+func requireServerFeatureDiff(meta interface{}, feature serverFeature) error {
+	client, ok := meta.(*Client)
+	if !ok {
+		return nil
+	}
+	return client.requireServerFeature(feature)
+}
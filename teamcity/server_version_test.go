@@ -0,0 +1,64 @@
+package teamcity_test
+
+import (
+	"testing"
+
+	"github.com/cvbarros/terraform-provider-teamcity/teamcity"
+)
+
+func TestParseServerVersion(t *testing.T) {
+	cases := []struct {
+		version  string
+		expected teamcity.ServerVersion
+	}{
+		{
+			version:  "2019.2.2 (build 71923)",
+			expected: teamcity.ServerVersion{Major: 2019, Minor: 2, Patch: 2, BuildNumber: "71923"},
+		},
+		{
+			version:  "2020.1 (build 78475)",
+			expected: teamcity.ServerVersion{Major: 2020, Minor: 1, BuildNumber: "78475"},
+		},
+		{
+			version:  "2017.1.5",
+			expected: teamcity.ServerVersion{Major: 2017, Minor: 1, Patch: 5},
+		},
+	}
+
+	for _, c := range cases {
+		actual, err := teamcity.ParseServerVersion(c.version)
+		if err != nil {
+			t.Errorf("ParseServerVersion(%q): unexpected error: %s", c.version, err)
+			continue
+		}
+		if *actual != c.expected {
+			t.Errorf("ParseServerVersion(%q): expected %+v but got %+v", c.version, c.expected, *actual)
+		}
+	}
+
+	if _, err := teamcity.ParseServerVersion("unknown"); err == nil {
+		t.Errorf("expected an error parsing an invalid version")
+	}
+}
+
+func TestServerVersion_AtLeast(t *testing.T) {
+	version := teamcity.ServerVersion{Major: 2019, Minor: 2, Patch: 2}
+
+	cases := []struct {
+		major    int
+		minor    int
+		expected bool
+	}{
+		{major: 2018, minor: 2, expected: true},
+		{major: 2019, minor: 1, expected: true},
+		{major: 2019, minor: 2, expected: true},
+		{major: 2019, minor: 3, expected: false},
+		{major: 2020, minor: 1, expected: false},
+	}
+
+	for _, c := range cases {
+		if actual := version.AtLeast(c.major, c.minor); actual != c.expected {
+			t.Errorf("AtLeast(%d, %d): expected %t but got %t", c.major, c.minor, c.expected, actual)
+		}
+	}
+}
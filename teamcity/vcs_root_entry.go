@@ -0,0 +1,61 @@
+package teamcity
+
+import (
+	"fmt"
+
+	api "github.com/cvbarros/go-teamcity/teamcity"
+)
+
+func vcsRootEntriesPath(buildConfigID string) string {
+	return fmt.Sprintf("buildTypes/%s/vcs-root-entries", api.LocatorID(buildConfigID))
+}
+
+func attachVcsRootEntry(r *restClient, buildConfigID string, entry *api.VcsRootEntry) error {
+	return r.post(vcsRootEntriesPath(buildConfigID), entry, nil)
+}
+
+func detachVcsRootEntry(r *restClient, buildConfigID string, vcsRootID string) error {
+	return r.delete(fmt.Sprintf("%s/%s", vcsRootEntriesPath(buildConfigID), vcsRootID))
+}
+
+func updateVcsRootEntryCheckoutRules(r *restClient, buildConfigID string, vcsRootID string, rules string) error {
+	return r.putText(fmt.Sprintf("%s/%s/checkout_rules", vcsRootEntriesPath(buildConfigID), vcsRootID), rules)
+}
+
+// updateVcsRootEntries changes the VCS Roots attached to a Build Configuration from the ones in from to the ones in to,
+// updating the checkout rules of the ones attached in both
+func updateVcsRootEntries(r *restClient, buildConfigID string, from, to []*api.VcsRootEntry) error {
+	attached := make(map[string]*api.VcsRootEntry)
+	for _, e := range from {
+		attached[e.VcsRoot.ID] = e
+	}
+	wanted := make(map[string]bool)
+	for _, e := range to {
+		wanted[e.VcsRoot.ID] = true
+	}
+
+	for _, e := range from {
+		if wanted[e.VcsRoot.ID] {
+			continue
+		}
+		if err := detachVcsRootEntry(r, buildConfigID, e.VcsRoot.ID); err != nil && !isNotFoundError(err) {
+			return err
+		}
+	}
+
+	for _, e := range to {
+		current, ok := attached[e.VcsRoot.ID]
+		if !ok {
+			if err := attachVcsRootEntry(r, buildConfigID, e); err != nil {
+				return err
+			}
+			continue
+		}
+		if current.CheckoutRules != e.CheckoutRules {
+			if err := updateVcsRootEntryCheckoutRules(r, buildConfigID, e.VcsRoot.ID, e.CheckoutRules); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}